@@ -0,0 +1,97 @@
+package taskengine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultLogCaptureLimit is the number of bytes of job logs kept per
+// execution when WithLogCapture is given no limit.
+const DefaultLogCaptureLimit = 64 << 10
+
+const truncatedMarker = "[log capture truncated]\n"
+
+type logBuffer struct {
+	mu sync.Mutex
+
+	buf       strings.Builder
+	limit     int
+	truncated bool
+}
+
+func (b *logBuffer) write(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.truncated {
+		return
+	}
+	if b.buf.Len()+len(line) > b.limit {
+		b.truncated = true
+		b.buf.WriteString(truncatedMarker)
+		return
+	}
+	b.buf.WriteString(line)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// captureLogger forwards to another logger and also keeps a copy of every
+// line, so that what a job logs can be saved with its execution.
+type captureLogger struct {
+	logger Logger
+	buffer *logBuffer
+	attrs  string
+}
+
+func (l *captureLogger) capture(level, msg string) {
+	l.buffer.write("[" + level + "] " + msg + l.attrs + "\n")
+}
+
+func (l *captureLogger) Info(msg string) {
+	l.logger.Info(msg)
+	l.capture("INFO", msg)
+}
+
+func (l *captureLogger) Infof(format string, args ...any) {
+	l.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Warn(msg string) {
+	l.logger.Warn(msg)
+	l.capture("WARN", msg)
+}
+
+func (l *captureLogger) Warnf(format string, args ...any) {
+	l.Warn(fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Error(msg string) {
+	l.logger.Error(msg)
+	l.capture("ERROR", msg)
+}
+
+func (l *captureLogger) Errorf(format string, args ...any) {
+	l.Error(fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) With(args ...any) Logger {
+	return &captureLogger{
+		logger: l.logger.With(args...),
+		buffer: l.buffer,
+		attrs:  l.attrs + formatAttrs(args),
+	}
+}
+
+func newCaptureLogger(logger Logger, limit int) *captureLogger {
+	return &captureLogger{
+		logger: logger,
+		buffer: &logBuffer{limit: limit},
+	}
+}
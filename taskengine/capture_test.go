@@ -0,0 +1,70 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTaskExecuteCapturesLogs(t *testing.T) {
+	job := func(ctx *Context) error {
+		ctx.Logger().Infof("attempt %d", ctx.Attempt())
+		ctx.Logger().With("rows", 3).Warn("partial load")
+		if ctx.Attempt() == 1 {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	task, s := newTestTask(t, job, WithLogCapture(0), WithRetry(RetryPolicy{MaxAttempts: 2}))
+	task.Execute(context.Background(), &Tick{})
+
+	executions := listExecutions(t, s)
+	if len(executions) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(executions))
+	}
+
+	for i, execution := range executions {
+		expected := fmt.Sprintf("[INFO] attempt %d\n[WARN] partial load rows=3\n", i+1)
+		if execution.Logs != expected {
+			t.Errorf("attempt %d: expected logs %q, got %q", i+1, expected, execution.Logs)
+		}
+	}
+}
+
+func TestTaskExecuteLogCaptureLimit(t *testing.T) {
+	job := func(ctx *Context) error {
+		for i := 0; i < 10; i++ {
+			ctx.Logger().Info(strings.Repeat("x", 10))
+		}
+		return nil
+	}
+
+	task, s := newTestTask(t, job, WithLogCapture(40))
+	task.Execute(context.Background(), &Tick{})
+
+	executions := listExecutions(t, s)
+	if len(executions) != 1 {
+		t.Fatalf("expected 1 execution, got %d", len(executions))
+	}
+
+	line := "[INFO] " + strings.Repeat("x", 10) + "\n"
+	expected := line + line + truncatedMarker
+	if got := executions[0].Logs; got != expected {
+		t.Errorf("expected logs %q, got %q", expected, got)
+	}
+}
+
+func TestTaskExecuteWithoutLogCapture(t *testing.T) {
+	task, s := newTestTask(t, func(ctx *Context) error {
+		ctx.Logger().Info("not captured")
+		return nil
+	})
+	task.Execute(context.Background(), &Tick{})
+
+	if got := listExecutions(t, s)[0].Logs; got != "" {
+		t.Errorf("expected no captured logs, got %q", got)
+	}
+}
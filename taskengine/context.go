@@ -18,7 +18,15 @@ type Context struct {
 
 	taskName string
 
+	attempt     int
+	executionID string
+
+	span   Span
 	logger Logger
+
+	// taskLogger logs for the engine; unlike logger, its lines are never
+	// captured with the execution.
+	taskLogger Logger
 }
 
 // ====================================
@@ -29,6 +37,31 @@ func (c *Context) Logger() Logger { return c.logger }
 
 func (c *Context) TaskName() string { return c.taskName }
 
+// ExecutionID returns the id shared by the log lines and attempts of the
+// current execution.
+func (c *Context) ExecutionID() string { return c.executionID }
+
+// Span returns the span of the current execution attempt, which is also
+// carried by the context for tracer integrations to find.
+func (c *Context) Span() Span { return c.span }
+
+// Attempt returns the attempt number of the current execution, starting at
+// 1 and increased on every retry.
+func (c *Context) Attempt() int { return c.attempt }
+
+// Manual reports whether the execution was requested through Engine.RunNow
+// rather than fired by the task trigger.
+func (c *Context) Manual() bool { return c.tick.manual }
+
+// Params returns the parameters passed to Engine.RunNow, or nil for
+// scheduled executions.
+func (c *Context) Params() map[string]any { return c.tick.params }
+
+func (c *Context) Param(key string) (any, bool) {
+	value, ok := c.tick.params[key]
+	return value, ok
+}
+
 func (c *Context) LastTick() time.Time { return c.tick.lastTick }
 
 func (c *Context) CurrentTick() time.Time { return c.tick.currentTick }
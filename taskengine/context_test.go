@@ -15,6 +15,7 @@ func (m *mockLogger) Warn(msg string)                        {}
 func (m *mockLogger) Warnf(format string, args ...any)      {}
 func (m *mockLogger) Error(msg string)                       {}
 func (m *mockLogger) Errorf(format string, args ...any)     {}
+func (m *mockLogger) With(args ...any) Logger                { return m }
 
 func TestContextLogger(t *testing.T) {
 	logger := &mockLogger{}
@@ -34,6 +35,37 @@ func TestContextTaskName(t *testing.T) {
 	}
 }
 
+func TestContextAttempt(t *testing.T) {
+	want := 2
+	ctx := &Context{attempt: want}
+
+	if got := ctx.Attempt(); got != want {
+		t.Errorf("expected attempt %d, got %d", want, got)
+	}
+}
+
+func TestContextManualParams(t *testing.T) {
+	tick := &Tick{manual: true, params: map[string]any{"key": "value"}}
+	ctx := &Context{tick: tick}
+
+	if !ctx.Manual() {
+		t.Error("expected manual execution")
+	}
+
+	if got, ok := ctx.Param("key"); !ok || got != "value" {
+		t.Errorf("expected param value, got %v (ok=%v)", got, ok)
+	}
+
+	if _, ok := ctx.Param("missing"); ok {
+		t.Error("expected missing param to be reported as absent")
+	}
+
+	scheduled := &Context{tick: &Tick{}}
+	if scheduled.Manual() || scheduled.Params() != nil {
+		t.Error("expected scheduled execution without params")
+	}
+}
+
 func TestContextLastTick(t *testing.T) {
 	want := time.Now().Add(-time.Hour)
 	tick := &Tick{lastTick: want}
@@ -1,13 +1,20 @@
 package taskengine
 
-import (
-	"errors"
-	"time"
-)
+import "time"
 
 type Tick struct {
 	lastTick    time.Time
 	currentTick time.Time
+
+	manual bool
+	params map[string]any
+
+	// parentExecutionID is set on the ticks a workflow fires for its tasks.
+	parentExecutionID string
+
+	// counter is set on dispatched ticks whose trigger counts occurrences,
+	// until the tick is settled.
+	counter *occurrenceCounter
 }
 
 type Dispatcher interface {
@@ -35,7 +42,7 @@ func (d *dispatcher) Enqueue(tick *Tick) error {
 	case d.queue <- tick:
 		return nil
 	default:
-		return errors.New("dispatcher queue is full")
+		return ErrorDispatcherFull
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -18,9 +19,44 @@ type Engine struct {
 
 	ctx             context.Context
 	shutdownTimeout time.Duration
+	nodeID          string
 
 	supervisors map[string]*WorkerSupervisor
 
+	retention         *store.RetentionPolicy
+	retentionInterval time.Duration
+	janitor           *backgroundLoop
+
+	elector          LeaderElector
+	electionInterval time.Duration
+	election         *backgroundLoop
+	leader           atomic.Bool
+
+	metrics         MetricsSink
+	metricsInterval time.Duration
+	metricsSampler  *backgroundLoop
+
+	tracer Tracer
+
+	middleware []Middleware
+	workflows  map[string]*Workflow
+	// workflowTasks maps the tasks of registered workflows, which have no
+	// supervisor of their own, to their workflow.
+	workflowTasks map[string]*Workflow
+
+	events      *eventBus
+	eventBuffer int
+
+	sharded           bool
+	heartbeatInterval time.Duration
+	nodeTTL           time.Duration
+	membership        *backgroundLoop
+	// owned holds the tasks the hash ring assigned to this engine on the
+	// last rebalance, and stopped the tasks shut down through ShutdownTask,
+	// which rebalancing never starts again.
+	owned   map[string]bool
+	stopped map[string]bool
+
 	store         store.Store
 	logger        Logger
 	loggerFactory LoggerFactory
@@ -40,7 +76,30 @@ func (e *Engine) Start() {
 	defer e.mu.Unlock()
 
 	e.logger.Info("Starting Task Engine...")
+	if !e.sharded {
+		e.startSupervisors()
+	} else if e.membership == nil {
+		// Tasks are started once the hash ring assigns them to this engine.
+		e.membership = startBackgroundLoop(e.ctx, e.runMembership)
+	}
+
+	if e.metrics != nil && e.metricsSampler == nil {
+		e.metricsSampler = startBackgroundLoop(e.ctx, e.runMetrics)
+	}
+	if e.retention != nil && e.janitor == nil {
+		e.janitor = startBackgroundLoop(e.ctx, e.runJanitor)
+	}
+	if e.elector != nil && e.election == nil {
+		e.election = startBackgroundLoop(e.ctx, e.runElection)
+	}
+	e.logger.Infof("Task Engine started with %d supervisors", len(e.supervisors))
+}
+
+func (e *Engine) startSupervisors() {
 	for _, s := range e.supervisors {
+		if s.Status() == workerSupervisorCompleted {
+			continue
+		}
 		err := e.store.UpdateTaskStatus(
 			s.worker.task.name, store.TaskStatusRunning,
 		)
@@ -50,16 +109,51 @@ func (e *Engine) Start() {
 			)
 			continue
 		}
-		s.Start(e.ctx)
+		s.start(e.ctx, !e.IsLeader())
 	}
-	e.logger.Infof("Task Engine started with %d supervisors", len(e.supervisors))
 }
 
 func (e *Engine) Shutdown() error {
+	e.logger.Info("Shutting down Task Engine...")
+
+	e.mu.Lock()
+	janitor, sampler, membership := e.janitor, e.metricsSampler, e.membership
+	e.janitor, e.metricsSampler, e.membership = nil, nil, nil
+	e.mu.Unlock()
+	janitor.stop()
+	sampler.stop()
+	membership.stop()
+
+	err := e.shutdownSupervisors()
+	if membership != nil {
+		e.leaveCluster()
+	}
+
+	// Leadership is only given up once no scheduler can dispatch anymore.
+	e.mu.Lock()
+	election := e.election
+	e.election = nil
+	e.mu.Unlock()
+	election.stop()
+
+	if flusher, ok := e.store.(store.Flusher); ok {
+		if flushErr := flusher.Flush(); flushErr != nil {
+			e.logger.Errorf("Failed to flush store: %v", flushErr)
+			if err == nil {
+				err = flushErr
+			}
+		}
+	}
+	return err
+}
+
+func (e *Engine) shutdownSupervisors() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.logger.Info("Shutting down Task Engine...")
+	// A restarted engine takes its tasks over from the hash ring again.
+	e.owned = make(map[string]bool)
+
 	var wg sync.WaitGroup
 	for _, s := range e.supervisors {
 		wg.Add(1)
@@ -92,12 +186,24 @@ func (e *Engine) Shutdown() error {
 	}
 }
 
+// StartTask starts the task name. A completed task stays completed, so it
+// fails with ErrorTaskCompleted once the trigger has no more ticks.
 func (e *Engine) StartTask(name string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	delete(e.stopped, name)
+	return e.startTask(name)
+}
+
+// startTask starts the supervisor of the task name. The caller must hold
+// e.mu.
+func (e *Engine) startTask(name string) error {
 	for _, s := range e.supervisors {
 		if s.worker.task.name == name {
+			if s.Status() == workerSupervisorCompleted {
+				return ErrorTaskCompleted
+			}
 			err := e.store.UpdateTaskStatus(
 				s.worker.task.name, store.TaskStatusRunning,
 			)
@@ -107,18 +213,28 @@ func (e *Engine) StartTask(name string) error {
 				)
 				continue
 			}
-			s.Start(e.ctx)
+			s.start(e.ctx, !e.IsLeader())
 			return nil
 		}
 	}
 	e.logger.Warnf("Task %s not found", name)
-	return errors.New("task not found")
+	return ErrorTaskNotFound
 }
 
 func (e *Engine) ShutdownTask(name string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	err := e.shutdownTask(name)
+	if !errors.Is(err, ErrorTaskNotFound) {
+		e.stopped[name] = true
+	}
+	return err
+}
+
+// shutdownTask stops the supervisor of the task name. The caller must hold
+// e.mu.
+func (e *Engine) shutdownTask(name string) error {
 	for _, s := range e.supervisors {
 		if s.worker.task.name == name {
 			done := make(chan struct{})
@@ -148,7 +264,7 @@ func (e *Engine) ShutdownTask(name string) error {
 		}
 	}
 	e.logger.Warnf("Task %s not found", name)
-	return errors.New("task not found")
+	return ErrorTaskNotFound
 }
 
 func (e *Engine) RegisterTask(
@@ -160,36 +276,22 @@ func (e *Engine) RegisterTask(
 ) error {
 	e.mu.Lock()
 	if _, exists := e.supervisors[task.name]; exists {
+		e.mu.Unlock()
 		e.logger.Warnf("Task '%s' is already registered", task.name)
 		return nil
 	}
+	if w, exists := e.workflowTasks[task.name]; exists {
+		e.mu.Unlock()
+		return fmt.Errorf("task '%s' of workflow '%s': %w", task.name, w.Name(), ErrorTaskAlreadyRegistered)
+	}
 	e.mu.Unlock()
 
 	e.logger.Infof("Registering task '%s' with policy '%s'", task.name, policy)
 
-	exists, err := e.store.TaskExists(task.name)
-	if err != nil {
+	if err := e.saveTask(task, policy, trigger); err != nil {
 		return err
 	}
-
-	if exists {
-		err := e.validateTaskSettings(task.name, task.jobName, policy, trigger)
-		if err != nil {
-			return err
-		}
-	} else {
-		err := e.store.SaveTask(task.name, &store.TaskSettings{
-			Job:     task.jobName,
-			Policy:  policy.String(),
-			Trigger: trigger.String(),
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	task.setLogger(e.loggerFactory)
-	task.setStore(e.store)
+	e.setupTask(task, policy)
 
 	lastTick, err := e.store.GetLastTick(task.name)
 	if err != nil {
@@ -200,18 +302,53 @@ func (e *Engine) RegisterTask(
 	dispatcher := newDispatcher(maxExecutionLag)
 
 	worker := newWorker(task, dispatcher, policy, e.loggerFactory(fmt.Sprintf("worker.%s", task.name)))
-	scheduler := newScheduler(trigger, dispatcher, catchUpEnabled, lastTick, e.loggerFactory(fmt.Sprintf("scheduler.%s", task.name)))
+	scheduler := newScheduler(task, trigger, dispatcher, catchUpEnabled, lastTick, e.loggerFactory(fmt.Sprintf("scheduler.%s", task.name)))
 	ws := newWorkerSupervisor(worker, scheduler, dispatcher, e.loggerFactory(fmt.Sprintf("workerSupervisor.%s", task.name)))
 
 	e.mu.Lock()
+	task.setEngineMiddleware(e.middleware)
 	e.supervisors[task.name] = ws
 	e.mu.Unlock()
 
 	e.logger.Infof("Task '%s' registered successfully", task.name)
+	e.events.publish(Event{Type: EventTaskRegistered, Task: task.name})
 
 	return nil
 }
 
+// saveTask persists the settings of a new task, or checks that they match
+// the settings persisted for it.
+func (e *Engine) saveTask(task *Task, policy workerPolicy, trigger Trigger) error {
+	exists, err := e.store.TaskExists(task.name)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return e.validateTaskSettings(task.name, task.jobName, policy, trigger)
+	}
+	return e.store.SaveTask(task.name, &store.TaskSettings{
+		Job:     task.jobName,
+		Policy:  policy.String(),
+		Trigger: trigger.String(),
+	})
+}
+
+// setupTask hands the engine dependencies over to task.
+func (e *Engine) setupTask(task *Task, policy workerPolicy) {
+	task.setLogger(e.loggerFactory)
+	task.setStore(e.store)
+	task.setNodeID(e.nodeID)
+	if e.metrics != nil {
+		task.setMetrics(e.metrics)
+	}
+	if e.tracer != nil {
+		task.setTracer(e.tracer)
+	}
+	task.setPolicy(policy)
+	task.setEvents(e.events)
+}
+
 func (e *Engine) validateTaskSettings(
 	taskName, jobName string, policy workerPolicy, trigger Trigger,
 ) error {
@@ -242,6 +379,14 @@ func (e *Engine) RemoveTask(name string) error {
 	if supervisor, exists := e.supervisors[name]; exists {
 		supervisor.Shutdown()
 		delete(e.supervisors, name)
+		delete(e.owned, name)
+		delete(e.stopped, name)
+		if w, exists := e.workflows[name]; exists {
+			for _, task := range w.tasks {
+				delete(e.workflowTasks, task.name)
+			}
+			delete(e.workflows, name)
+		}
 
 		err := e.store.UpdateTaskStatus(name, store.TaskStatusIdle)
 		if err != nil {
@@ -249,11 +394,22 @@ func (e *Engine) RemoveTask(name string) error {
 		}
 
 		e.logger.Infof("Task '%s' removed successfully", name)
+		e.events.publish(Event{Type: EventTaskRemoved, Task: name})
 		return nil
 	}
 
 	e.logger.Warnf("Task %s not found", name)
-	return errors.New("task not found")
+	return ErrorTaskNotFound
+}
+
+func (e *Engine) GetExecution(name string, iteration int) (*store.Execution, error) {
+	return e.store.GetExecution(name, iteration)
+}
+
+func (e *Engine) ListExecutions(
+	name string, filter *store.ExecutionFilter,
+) ([]*store.Execution, error) {
+	return e.store.ListExecutions(name, filter)
 }
 
 func New(store store.Store, options ...EngineOption) (*Engine, error) {
@@ -266,7 +422,14 @@ func New(store store.Store, options ...EngineOption) (*Engine, error) {
 		store:           store,
 		loggerFactory:   DefaultLoggerFactory,
 		supervisors:     make(map[string]*WorkerSupervisor),
+		workflows:       make(map[string]*Workflow),
+		workflowTasks:   make(map[string]*Workflow),
+		owned:           make(map[string]bool),
+		stopped:         make(map[string]bool),
 		shutdownTimeout: 30 * time.Second, // Default shutdown timeout
+		nodeID:          defaultNodeID(),
+		eventBuffer:     64, // Default event buffer per subscriber
+		middleware:      DefaultMiddleware(),
 	}
 
 	for _, opt := range options {
@@ -276,9 +439,44 @@ func New(store store.Store, options ...EngineOption) (*Engine, error) {
 	// Create engine logger after options are applied
 	engine.logger = engine.loggerFactory("engine")
 
+	engine.events = newEventBus(engine.eventBuffer, engine.logger)
+
 	return engine, nil
 }
 
+// backgroundLoop is a goroutine the engine runs next to its supervisors.
+type backgroundLoop struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startBackgroundLoop(
+	ctx context.Context, run func(context.Context),
+) *backgroundLoop {
+	ctx, cancel := context.WithCancel(ctx)
+	l := &backgroundLoop{cancel: cancel, done: make(chan struct{})}
+	go func() { defer close(l.done); run(ctx) }()
+	return l
+}
+
+func (l *backgroundLoop) stop() {
+	if l != nil {
+		l.cancel()
+		<-l.done
+	}
+}
+
+// NodeID returns the identity under which the engine claims ticks.
+func (e *Engine) NodeID() string { return e.nodeID }
+
+func defaultNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
 type EngineOption func(*Engine)
 
 func WithShutdownTimeout(timeout time.Duration) EngineOption {
@@ -287,8 +485,97 @@ func WithShutdownTimeout(timeout time.Duration) EngineOption {
 	}
 }
 
+// WithNodeID sets the identity under which the engine claims ticks. It must
+// be unique among the engines sharing a store and defaults to the hostname
+// and process id.
+func WithNodeID(id string) EngineOption {
+	return func(e *Engine) {
+		e.nodeID = id
+	}
+}
+
 func WithLoggerFactory(factory LoggerFactory) EngineOption {
 	return func(e *Engine) {
 		e.loggerFactory = factory
 	}
 }
+
+// WithRetention enables a background janitor that prunes the executions of
+// every registered task according to policy once per interval.
+func WithRetention(policy store.RetentionPolicy, interval time.Duration) EngineOption {
+	return func(e *Engine) {
+		if interval <= 0 {
+			interval = time.Hour // Default retention interval
+		}
+		e.retention = &policy
+		e.retentionInterval = interval
+	}
+}
+
+// WithLeaderElection makes the engine campaign for leadership through
+// elector once per interval. Only the leader dispatches scheduled ticks.
+func WithLeaderElection(elector LeaderElector, interval time.Duration) EngineOption {
+	return func(e *Engine) {
+		if interval <= 0 {
+			interval = 5 * time.Second // Default election interval
+		}
+		e.elector = elector
+		e.electionInterval = interval
+	}
+}
+
+// WithSharding spreads the registered tasks over the engines sharing the
+// store. Every engine heartbeats once per interval, and a task runs on the
+// engine a consistent-hash ring of the live nodes assigns it to. Nodes
+// without a heartbeat for ttl are considered gone. During a handoff a task
+// may briefly run on two engines; WithExactlyOnce covers that window.
+func WithSharding(interval, ttl time.Duration) EngineOption {
+	return func(e *Engine) {
+		if interval <= 0 {
+			interval = 5 * time.Second // Default heartbeat interval
+		}
+		if ttl <= interval {
+			ttl = 3 * interval
+		}
+		e.sharded = true
+		e.heartbeatInterval = interval
+		e.nodeTTL = ttl
+	}
+}
+
+// WithMetrics reports executions to sink as they are saved, and samples the
+// queue, in-flight and scheduler gauges of every task once per interval.
+func WithMetrics(sink MetricsSink, interval time.Duration) EngineOption {
+	return func(e *Engine) {
+		if interval <= 0 {
+			interval = 5 * time.Second // Default sampling interval
+		}
+		e.metrics = sink
+		e.metricsInterval = interval
+	}
+}
+
+// WithTracer starts a span with tracer around every execution attempt.
+func WithTracer(tracer Tracer) EngineOption {
+	return func(e *Engine) {
+		e.tracer = tracer
+	}
+}
+
+// WithMiddleware replaces the default middlewares of the engine with
+// middleware.
+func WithMiddleware(middleware ...Middleware) EngineOption {
+	return func(e *Engine) {
+		e.middleware = middleware
+	}
+}
+
+// WithEventBuffer sets how many events every subscription holds before
+// newer events are dropped for it.
+func WithEventBuffer(size int) EngineOption {
+	return func(e *Engine) {
+		if size > 0 {
+			e.eventBuffer = size
+		}
+	}
+}
@@ -6,5 +6,15 @@ var (
 	ErrorPolicyMismatch        = errors.New("policy mismatch")
 	ErrorJobNameMismatch       = errors.New("job name mismatch")
 	ErrorTriggerMismatch       = errors.New("trigger mismatch")
+	ErrorTaskNotFound          = errors.New("task not found")
+	ErrorTaskNotRunning        = errors.New("task is not running")
+	ErrorTaskCompleted         = errors.New("task is completed")
 	ErrorTaskAlreadyRegistered = errors.New("task is already registered")
+	ErrorDispatcherFull        = errors.New("dispatcher queue is full")
+	ErrorDependencyNotFound    = errors.New("dependency not found")
+	ErrorDependencyCycle       = errors.New("dependency cycle")
+
+	// ErrorNoMoreTicks is returned by Trigger.Next once the trigger will
+	// not fire again, which completes the task.
+	ErrorNoMoreTicks = errors.New("trigger has no more ticks")
 )
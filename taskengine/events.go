@@ -0,0 +1,256 @@
+package taskengine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+type EventType string
+
+const (
+	EventTaskRegistered     EventType = "task_registered"
+	EventTaskRemoved        EventType = "task_removed"
+	EventSupervisorStarted  EventType = "supervisor_started"
+	EventSupervisorStopped  EventType = "supervisor_stopped"
+	EventSchedulerPaused    EventType = "scheduler_paused"
+	EventSchedulerResumed   EventType = "scheduler_resumed"
+	EventTickDispatched     EventType = "tick_dispatched"
+	EventTickDropped        EventType = "tick_dropped"
+	EventExecutionStarted   EventType = "execution_started"
+	EventExecutionSucceeded EventType = "execution_succeeded"
+	EventExecutionFailed    EventType = "execution_failed"
+	EventExecutionPanicked  EventType = "execution_panicked"
+
+	// EventExecutionCompleted follows the last attempt of an execution, once
+	// retries are over, and carries its outcome.
+	EventExecutionCompleted EventType = "execution_completed"
+
+	// EventSupervisorCompleted is published once the trigger of the task has
+	// no more ticks and the dispatched ones were executed.
+	EventSupervisorCompleted EventType = "supervisor_completed"
+)
+
+type Event struct {
+	Type EventType
+	Task string
+	Time time.Time
+
+	// Tick and LastTick are set for tick and execution events.
+	Tick     time.Time
+	LastTick time.Time
+	Manual   bool
+
+	// Attempt is set for execution events.
+	Attempt int
+
+	// Execution is what was saved for finished executions and dropped
+	// ticks.
+	Execution *store.ExecutionInfo
+}
+
+// EventFilter selects events by type and task. Empty fields match
+// everything.
+type EventFilter struct {
+	Types []EventType
+	Tasks []string
+}
+
+func (f *EventFilter) matches(event *Event) bool {
+	return contains(f.Types, event.Type) && contains(f.Tasks, event.Task)
+}
+
+func contains[T comparable](values []T, value T) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type subscription struct {
+	filter EventFilter
+	events chan Event
+
+	// queue replaces events for lossless subscriptions.
+	queue *eventQueue
+
+	// lagging is set from the first event dropped for the subscription
+	// until the next one delivered, so a slow subscriber is logged once.
+	lagging atomic.Bool
+}
+
+// eventQueue is an unbounded FIFO of events, for subscribers that must see
+// every event, like the triggers fired by other tasks.
+type eventQueue struct {
+	mu     sync.Mutex
+	events []Event
+
+	// ready holds a signal whenever events were pushed since the last pop
+	// that emptied the queue.
+	ready chan struct{}
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{ready: make(chan struct{}, 1)}
+}
+
+func (q *eventQueue) push(event Event) {
+	q.mu.Lock()
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the oldest event of the queue, if any.
+func (q *eventQueue) pop() (Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.events) == 0 {
+		return Event{}, false
+	}
+	event := q.events[0]
+	q.events[0] = Event{}
+	q.events = q.events[1:]
+	return event, true
+}
+
+// eventBus delivers events to subscribers without ever blocking the
+// publisher: an event that does not fit in a subscriber's buffer is dropped
+// for that subscriber, while lossless subscribers queue every event.
+type eventBus struct {
+	mu sync.RWMutex
+
+	subscriptions map[*subscription]struct{}
+	bufferSize    int
+
+	dropped atomic.Uint64
+	logger  Logger
+}
+
+// subscribe on a nil bus returns a subscription that never receives events.
+func (b *eventBus) subscribe(filter EventFilter) (*subscription, func()) {
+	if b == nil {
+		return &subscription{filter: filter}, func() {}
+	}
+	return b.add(&subscription{filter: filter, events: make(chan Event, b.bufferSize)})
+}
+
+// subscribeLossless subscribes to events that must never be dropped. They
+// are queued on the subscription, which never blocks the publisher but
+// grows for as long as the subscriber does not pop them.
+func (b *eventBus) subscribeLossless(filter EventFilter) (*subscription, func()) {
+	sub := &subscription{filter: filter, queue: newEventQueue()}
+	if b == nil {
+		return sub, func() {}
+	}
+	return b.add(sub)
+}
+
+func (b *eventBus) add(sub *subscription) (*subscription, func()) {
+	b.mu.Lock()
+	b.subscriptions[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscriptions, sub)
+			if sub.events != nil {
+				close(sub.events)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// publish is a no-op on a nil bus, so tasks run outside an engine need no
+// bus.
+func (b *eventBus) publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscriptions {
+		if !sub.filter.matches(&event) {
+			continue
+		}
+		if sub.queue != nil {
+			sub.queue.push(event)
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+			sub.lagging.Store(false)
+		default:
+			dropped := b.dropped.Add(1)
+			if !sub.lagging.Swap(true) {
+				b.logger.Warnf(
+					"Dropped event %s of task '%s' for a subscriber with a full buffer (%d dropped in total)",
+					event.Type, event.Task, dropped,
+				)
+			}
+		}
+	}
+}
+
+func newEventBus(bufferSize int, logger Logger) *eventBus {
+	return &eventBus{
+		subscriptions: make(map[*subscription]struct{}),
+		bufferSize:    bufferSize,
+		logger:        logger,
+	}
+}
+
+// DroppedEvents returns how many events were dropped so far because a
+// subscriber's buffer was full.
+func (e *Engine) DroppedEvents() uint64 { return e.events.dropped.Load() }
+
+// Subscribe returns a channel receiving the events matching filter, and a
+// function that ends the subscription and closes the channel. Events that
+// arrive while the channel buffer is full are dropped.
+func (e *Engine) Subscribe(filter EventFilter) (<-chan Event, func()) {
+	sub, unsubscribe := e.events.subscribe(filter)
+	return sub.events, unsubscribe
+}
+
+// SubscribeFunc calls fn, from a goroutine of its own, for every event
+// matching filter. It returns a function that ends the subscription.
+func (e *Engine) SubscribeFunc(filter EventFilter, fn func(Event)) func() {
+	events, unsubscribe := e.Subscribe(filter)
+	go func() {
+		for event := range events {
+			fn(event)
+		}
+	}()
+	return unsubscribe
+}
+
+func tickEvent(eventType EventType, task string, tick *Tick) Event {
+	return Event{
+		Type:     eventType,
+		Task:     task,
+		Tick:     tick.currentTick,
+		LastTick: tick.lastTick,
+		Manual:   tick.manual,
+	}
+}
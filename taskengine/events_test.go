@@ -0,0 +1,189 @@
+package taskengine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected an event")
+		return Event{}
+	}
+}
+
+func TestEngineEvents(t *testing.T) {
+	engine := newTestEngine(t)
+	events, unsubscribe := engine.Subscribe(EventFilter{})
+	defer unsubscribe()
+
+	trigger, _ := NewIntervalTrigger(time.Hour, true)
+	registerTestTask(t, engine, "task", trigger)
+
+	engine.Start()
+	expected := []EventType{
+		EventTaskRegistered,
+		EventSupervisorStarted,
+		EventTickDispatched,
+		EventExecutionStarted,
+		EventExecutionSucceeded,
+		EventExecutionCompleted,
+	}
+	for _, eventType := range expected {
+		event := nextEvent(t, events)
+		if event.Type != eventType || event.Task != "task" {
+			t.Fatalf("expected %s event for task, got %s for %q", eventType, event.Type, event.Task)
+		}
+		if event.Time.IsZero() {
+			t.Errorf("expected %s event to have a time", eventType)
+		}
+		if eventType == EventExecutionSucceeded {
+			if event.Attempt != 1 || event.Execution == nil || event.Tick.IsZero() {
+				t.Errorf("expected first attempt with tick and execution, got %+v", event)
+			}
+		}
+	}
+
+	if err := engine.RemoveTask("task"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, eventType := range []EventType{EventSupervisorStopped, EventTaskRemoved} {
+		if event := nextEvent(t, events); event.Type != eventType {
+			t.Fatalf("expected %s event, got %s", eventType, event.Type)
+		}
+	}
+	engine.Shutdown()
+}
+
+func TestEngineEventsFilter(t *testing.T) {
+	engine := newTestEngine(t)
+	events, unsubscribe := engine.Subscribe(EventFilter{
+		Types: []EventType{EventExecutionFailed, EventExecutionPanicked},
+		Tasks: []string{"fail", "panic"},
+	})
+	defer unsubscribe()
+
+	trigger, _ := NewIntervalTrigger(time.Hour, true)
+	jobs := map[string]Job{
+		"ok":    noopJob,
+		"fail":  func(ctx *Context) error { return errors.New("failed") },
+		"panic": func(ctx *Context) error { panic("boom") },
+	}
+	for name, job := range jobs {
+		task, err := NewTask(name, job)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	got := map[string]EventType{}
+	for i := 0; i < 2; i++ {
+		event := nextEvent(t, events)
+		got[event.Task] = event.Type
+	}
+	if got["fail"] != EventExecutionFailed || got["panic"] != EventExecutionPanicked {
+		t.Errorf("expected failed and panicked events, got %v", got)
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("expected no more events, got %s for %q", event.Type, event.Task)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEngineSubscribeFunc(t *testing.T) {
+	engine := newTestEngine(t)
+
+	received := make(chan Event, 1)
+	unsubscribe := engine.SubscribeFunc(
+		EventFilter{Types: []EventType{EventTaskRegistered}},
+		func(event Event) { received <- event },
+	)
+	defer unsubscribe()
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	registerTestTask(t, engine, "task", trigger)
+
+	if event := nextEvent(t, received); event.Task != "task" {
+		t.Errorf("expected registered event for task, got %q", event.Task)
+	}
+}
+
+func TestEventBusDropsWhenFull(t *testing.T) {
+	bus := newEventBus(1, &mockLogger{})
+	sub, unsubscribe := bus.subscribe(EventFilter{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			bus.publish(Event{Type: EventTickDispatched, Attempt: i})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected publish not to block on a full subscriber")
+	}
+
+	if event := <-sub.events; event.Attempt != 0 {
+		t.Errorf("expected the first event to be kept, got attempt %d", event.Attempt)
+	}
+	if dropped := bus.dropped.Load(); dropped != 2 {
+		t.Errorf("expected 2 dropped events, got %d", dropped)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-sub.events; ok {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+
+	bus.publish(Event{Type: EventTickDispatched})
+
+	var nilBus *eventBus
+	nilBus.publish(Event{Type: EventTickDispatched})
+}
+
+func TestEventBusLosslessSubscription(t *testing.T) {
+	bus := newEventBus(1, &mockLogger{})
+	sub, unsubscribe := bus.subscribeLossless(EventFilter{Types: []EventType{EventExecutionCompleted}})
+	defer unsubscribe()
+
+	for i := 0; i < 10; i++ {
+		bus.publish(Event{Type: EventExecutionCompleted, Attempt: i})
+		bus.publish(Event{Type: EventTickDispatched})
+	}
+
+	select {
+	case <-sub.queue.ready:
+	default:
+		t.Fatal("expected the queue to signal pushed events")
+	}
+	for i := 0; i < 10; i++ {
+		event, ok := sub.queue.pop()
+		if !ok || event.Attempt != i {
+			t.Fatalf("expected event %d in order, got %v (%v)", i, event.Attempt, ok)
+		}
+	}
+	if _, ok := sub.queue.pop(); ok {
+		t.Error("expected only matching events to be queued")
+	}
+	if dropped := bus.dropped.Load(); dropped != 0 {
+		t.Errorf("expected no dropped events, got %d", dropped)
+	}
+}
@@ -0,0 +1,55 @@
+package taskengine
+
+import "github.com/MAD-py/go-taskengine/taskengine/store"
+
+type (
+	StartHook   = func(ctx *Context) error
+	SuccessHook = func(ctx *Context, info *store.ExecutionInfo) error
+	FailureHook = func(ctx *Context, info *store.ExecutionInfo, err error) error
+	PanicHook   = func(ctx *Context, info *store.ExecutionInfo, recovered any) error
+)
+
+type hooks struct {
+	onStart   []StartHook
+	onSuccess []SuccessHook
+	onFailure []FailureHook
+	onPanic   []PanicHook
+}
+
+// runHook calls hook and logs the error it returns or the panic it raises,
+// so that hooks never change the outcome of an execution.
+func runHook(logger Logger, kind string, hook func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("PANIC in %s hook: %v", kind, r)
+		}
+	}()
+
+	if err := hook(); err != nil {
+		logger.Errorf("%s hook failed: %v", kind, err)
+	}
+}
+
+func (h *hooks) start(logger Logger, ctx *Context) {
+	for _, hook := range h.onStart {
+		runHook(logger, "OnStart", func() error { return hook(ctx) })
+	}
+}
+
+func (h *hooks) success(logger Logger, ctx *Context, info *store.ExecutionInfo) {
+	for _, hook := range h.onSuccess {
+		runHook(logger, "OnSuccess", func() error { return hook(ctx, info) })
+	}
+}
+
+func (h *hooks) failure(logger Logger, ctx *Context, info *store.ExecutionInfo, err error) {
+	for _, hook := range h.onFailure {
+		runHook(logger, "OnFailure", func() error { return hook(ctx, info, err) })
+	}
+}
+
+func (h *hooks) panicked(logger Logger, ctx *Context, info *store.ExecutionInfo, recovered any) {
+	for _, hook := range h.onPanic {
+		runHook(logger, "OnPanic", func() error { return hook(ctx, info, recovered) })
+	}
+}
@@ -0,0 +1,93 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+func TestTaskHooks(t *testing.T) {
+	jobErr := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		job      Job
+		expected []string
+		status   store.ExecutionStatus
+	}{
+		{"success", noopJob, []string{"start", "success"}, store.ExecutionStatusSuccess},
+		{"failure", func(ctx *Context) error { return jobErr }, []string{"start", "failure"}, store.ExecutionStatusError},
+		{"panic", func(ctx *Context) error { panic("boom") }, []string{"start", "panic"}, store.ExecutionStatusPanic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			var got *store.ExecutionInfo
+
+			task, _ := newTestTask(t, tt.job,
+				WithOnStart(func(ctx *Context) error {
+					if ctx.TaskName() != "task" || ctx.Attempt() != 1 {
+						t.Errorf("expected context of task attempt 1, got %s attempt %d", ctx.TaskName(), ctx.Attempt())
+					}
+					calls = append(calls, "start")
+					return nil
+				}),
+				WithOnSuccess(func(ctx *Context, info *store.ExecutionInfo) error {
+					calls, got = append(calls, "success"), info
+					return nil
+				}),
+				WithOnFailure(func(ctx *Context, info *store.ExecutionInfo, err error) error {
+					if !errors.Is(err, jobErr) {
+						t.Errorf("expected job error, got %v", err)
+					}
+					calls, got = append(calls, "failure"), info
+					return nil
+				}),
+				WithOnPanic(func(ctx *Context, info *store.ExecutionInfo, recovered any) error {
+					if recovered != "boom" {
+						t.Errorf("expected recovered value boom, got %v", recovered)
+					}
+					calls, got = append(calls, "panic"), info
+					return nil
+				}),
+			)
+
+			task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+			if len(calls) != len(tt.expected) || calls[0] != tt.expected[0] || calls[1] != tt.expected[1] {
+				t.Fatalf("expected hooks %v, got %v", tt.expected, calls)
+			}
+			if got == nil || got.Status != tt.status {
+				t.Errorf("expected execution with status %s, got %+v", tt.status, got)
+			}
+		})
+	}
+}
+
+func TestTaskHookFailuresAreIsolated(t *testing.T) {
+	ran := 0
+	task, s := newTestTask(t, noopJob,
+		WithOnStart(func(ctx *Context) error { panic("hook") }),
+		WithOnSuccess(func(ctx *Context, info *store.ExecutionInfo) error {
+			return errors.New("hook failed")
+		}),
+		WithOnSuccess(func(ctx *Context, info *store.ExecutionInfo) error {
+			ran++
+			return nil
+		}),
+	)
+
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	if ran != 1 {
+		t.Errorf("expected the second success hook to run once, got %d", ran)
+	}
+	executions := listExecutions(t, s)
+	if len(executions) != 1 || executions[0].Status != store.ExecutionStatusSuccess {
+		t.Fatalf("expected one successful execution, got %+v", executions)
+	}
+}
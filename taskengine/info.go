@@ -0,0 +1,141 @@
+package taskengine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// TaskInfo is a point-in-time snapshot of a registered task, combining the
+// live state of its supervisor with what the store has persisted.
+type TaskInfo struct {
+	Name    string
+	Job     string
+	Policy  workerPolicy
+	Trigger string
+
+	SupervisorStatus workerSupervisorState
+	WorkerStatus     workerState
+	SchedulerStatus  schedulerState
+
+	QueueSize     int
+	QueueCapacity int
+	InFlight      int
+	NextTick      time.Time
+
+	// Workflow names the workflow a task runs in. Such tasks have no
+	// supervisor, so their live state is left zero.
+	Workflow string
+
+	Settings *store.TaskSettings
+	Status   store.TaskStatus
+}
+
+func (e *Engine) Tasks() ([]*TaskInfo, error) {
+	e.mu.Lock()
+	supervisors := make([]*WorkerSupervisor, 0, len(e.supervisors))
+	for _, s := range e.supervisors {
+		supervisors = append(supervisors, s)
+	}
+	workflowTasks := make(map[string]*Workflow, len(e.workflowTasks))
+	for name, w := range e.workflowTasks {
+		workflowTasks[name] = w
+	}
+	e.mu.Unlock()
+
+	tasks := make([]*TaskInfo, 0, len(supervisors)+len(workflowTasks))
+	for _, s := range supervisors {
+		info, err := e.taskInfo(s)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, info)
+	}
+	for name, w := range workflowTasks {
+		info, err := e.workflowTaskInfo(w, name)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, info)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks, nil
+}
+
+func (e *Engine) Task(name string) (*TaskInfo, error) {
+	e.mu.Lock()
+	s, exists := e.supervisors[name]
+	w, inWorkflow := e.workflowTasks[name]
+	e.mu.Unlock()
+
+	switch {
+	case exists:
+		return e.taskInfo(s)
+	case inWorkflow:
+		return e.workflowTaskInfo(w, name)
+	default:
+		return nil, ErrorTaskNotFound
+	}
+}
+
+func (e *Engine) taskInfo(s *WorkerSupervisor) (*TaskInfo, error) {
+	task := s.worker.task
+
+	settings, status, err := e.persistedTask(task.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskInfo{
+		Name:             task.name,
+		Job:              task.jobName,
+		Policy:           s.worker.policy,
+		Trigger:          s.scheduler.trigger.String(),
+		SupervisorStatus: s.Status(),
+		WorkerStatus:     s.WorkerStatus(),
+		SchedulerStatus:  s.SchedulerStatus(),
+		QueueSize:        s.dispatcher.Size(),
+		QueueCapacity:    s.dispatcher.Capacity(),
+		InFlight:         s.InFlight(),
+		NextTick:         s.scheduler.NextTick(),
+		Settings:         settings,
+		Status:           status,
+	}, nil
+}
+
+// workflowTaskInfo describes a task of workflow w. It has no supervisor, so
+// only what the store persisted is reported besides its definition.
+func (e *Engine) workflowTaskInfo(w *Workflow, name string) (*TaskInfo, error) {
+	task := w.taskNamed(name)
+
+	settings, status, err := e.persistedTask(name)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := &workflowTaskTrigger{workflow: w.Name(), upstream: task.upstream}
+	return &TaskInfo{
+		Name:     name,
+		Job:      task.jobName,
+		Policy:   task.policy,
+		Trigger:  trigger.String(),
+		Workflow: w.Name(),
+		Settings: settings,
+		Status:   status,
+	}, nil
+}
+
+func (e *Engine) persistedTask(name string) (*store.TaskSettings, store.TaskStatus, error) {
+	settings, err := e.store.GetTaskSettings(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	status, err := e.store.GetTaskStatus(name)
+	if err != nil {
+		return nil, "", err
+	}
+	return settings, status, nil
+}
@@ -0,0 +1,104 @@
+package taskengine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func noopJob(ctx *Context) error { return nil }
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	engine, err := New(
+		memory.NewStore(),
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return engine
+}
+
+func registerTestTask(t *testing.T, engine *Engine, name string, trigger Trigger) {
+	t.Helper()
+
+	task, err := NewTask(name, noopJob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEngineTasks(t *testing.T) {
+	engine := newTestEngine(t)
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	registerTestTask(t, engine, "b", trigger)
+	registerTestTask(t, engine, "a", trigger)
+
+	tasks, err := engine.Tasks()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tasks) != 2 || tasks[0].Name != "a" || tasks[1].Name != "b" {
+		t.Fatalf("expected tasks a and b sorted by name, got %+v", tasks)
+	}
+
+	info := tasks[0]
+	if info.Policy != WorkerPolicySerial {
+		t.Errorf("expected policy %s, got %s", WorkerPolicySerial, info.Policy)
+	}
+	if info.Trigger != trigger.String() {
+		t.Errorf("expected trigger %s, got %s", trigger, info.Trigger)
+	}
+	if info.SupervisorStatus != workerSupervisorIdle || info.SchedulerStatus != schedulerIdle {
+		t.Errorf("expected idle supervisor and scheduler, got %s and %s", info.SupervisorStatus, info.SchedulerStatus)
+	}
+	if info.QueueCapacity != 5 || info.QueueSize != 0 {
+		t.Errorf("expected empty queue of capacity 5, got %d/%d", info.QueueSize, info.QueueCapacity)
+	}
+	if until := time.Until(info.NextTick); until <= 0 || until > time.Hour {
+		t.Errorf("expected next tick within the next hour, got %v", info.NextTick)
+	}
+	if info.Status != store.TaskStatusIdle {
+		t.Errorf("expected persisted status %s, got %s", store.TaskStatusIdle, info.Status)
+	}
+	if info.Settings == nil || info.Settings.Policy != WorkerPolicySerial.String() {
+		t.Errorf("expected persisted settings with policy %s, got %+v", WorkerPolicySerial, info.Settings)
+	}
+}
+
+func TestEngineTask(t *testing.T) {
+	engine := newTestEngine(t)
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	registerTestTask(t, engine, "task", trigger)
+
+	if err := engine.StartTask("task"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer engine.ShutdownTask("task")
+
+	info, err := engine.Task("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.SupervisorStatus != workerSupervisorRunning {
+		t.Errorf("expected running supervisor, got %s", info.SupervisorStatus)
+	}
+	if info.Status != store.TaskStatusRunning {
+		t.Errorf("expected persisted status %s, got %s", store.TaskStatusRunning, info.Status)
+	}
+
+	if _, err := engine.Task("missing"); !errors.Is(err, ErrorTaskNotFound) {
+		t.Errorf("expected ErrorTaskNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package taskengine
+
+import (
+	"context"
+	"time"
+)
+
+func (e *Engine) runJanitor(ctx context.Context) {
+	ticker := time.NewTicker(e.retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.pruneExecutions()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Engine) pruneExecutions() {
+	e.mu.Lock()
+	names := make([]string, 0, len(e.supervisors)+len(e.workflowTasks))
+	for name := range e.supervisors {
+		names = append(names, name)
+	}
+	for name := range e.workflowTasks {
+		names = append(names, name)
+	}
+	e.mu.Unlock()
+
+	for _, name := range names {
+		pruned, err := e.store.PruneExecutions(name, e.retention)
+		if err != nil {
+			e.logger.Errorf("Failed to prune executions of task '%s': %v", name, err)
+			continue
+		}
+		if pruned > 0 {
+			e.logger.Infof("Pruned %d executions of task '%s'", pruned, name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package taskengine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func TestJanitorPrunesExecutions(t *testing.T) {
+	ms := memory.NewStore()
+	engine, err := New(
+		ms,
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+		WithRetention(store.RetentionPolicy{MaxRows: 2}, 10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	registerTestTask(t, engine, "task", trigger)
+
+	for i := 0; i < 5; i++ {
+		if err := ms.SaveExecution("task", &store.ExecutionInfo{Status: store.ExecutionStatusSuccess}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		executions, err := engine.ListExecutions("task", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(executions) == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected janitor to keep 2 executions, got %d", len(executions))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+package taskengine
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderElector decides which of several engines sharing a store runs the
+// scheduled tasks. Followers keep their supervisors running with paused
+// schedulers, so they can take over as soon as they win an election.
+type LeaderElector interface {
+	// TryAcquire acquires or renews leadership and reports whether this
+	// engine holds it.
+	TryAcquire() (bool, error)
+
+	// Release gives up leadership if it is held.
+	Release() error
+}
+
+// IsLeader reports whether the engine's schedulers may dispatch ticks. It is
+// always true when no LeaderElector is configured.
+func (e *Engine) IsLeader() bool { return e.elector == nil || e.leader.Load() }
+
+func (e *Engine) runElection(ctx context.Context) {
+	ticker := time.NewTicker(e.electionInterval)
+	defer ticker.Stop()
+
+	for {
+		e.campaign()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			e.resign()
+			return
+		}
+	}
+}
+
+func (e *Engine) campaign() {
+	leader, err := e.elector.TryAcquire()
+	if err != nil {
+		e.logger.Errorf("Leader election failed: %v", err)
+		leader = false
+	}
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+
+	if leader {
+		e.logger.Info("Acquired leadership, resuming schedulers")
+	} else {
+		e.logger.Warn("Lost leadership, pausing schedulers")
+	}
+	e.setSchedulersPaused(!leader)
+}
+
+func (e *Engine) resign() {
+	if !e.leader.Swap(false) {
+		return
+	}
+
+	e.setSchedulersPaused(true)
+	if err := e.elector.Release(); err != nil {
+		e.logger.Errorf("Failed to release leadership: %v", err)
+		return
+	}
+	e.logger.Info("Released leadership")
+}
+
+func (e *Engine) setSchedulersPaused(paused bool) {
+	e.mu.Lock()
+	supervisors := make([]*WorkerSupervisor, 0, len(e.supervisors))
+	for _, s := range e.supervisors {
+		supervisors = append(supervisors, s)
+	}
+	e.mu.Unlock()
+
+	for _, s := range supervisors {
+		if s.Status() != workerSupervisorRunning {
+			continue
+		}
+		if paused {
+			s.PauseScheduler()
+		} else {
+			s.ResumeScheduler()
+		}
+	}
+}
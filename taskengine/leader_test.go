@@ -0,0 +1,82 @@
+package taskengine
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+	"github.com/MAD-py/go-taskengine/taskengine/store/postgresql"
+)
+
+var _ LeaderElector = (*postgresql.LeaderElector)(nil)
+
+type mockElector struct {
+	leader   atomic.Bool
+	released atomic.Bool
+}
+
+func (m *mockElector) TryAcquire() (bool, error) { return m.leader.Load(), nil }
+
+func (m *mockElector) Release() error {
+	m.released.Store(true)
+	return nil
+}
+
+func TestLeaderElection(t *testing.T) {
+	elector := &mockElector{}
+	engine, err := New(
+		memory.NewStore(),
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+		WithLeaderElection(elector, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, _ := NewIntervalTrigger(5*time.Millisecond, false)
+	registerTestTask(t, engine, "task", trigger)
+
+	schedulerStatus := func() schedulerState {
+		return engine.supervisors["task"].SchedulerStatus()
+	}
+	executions := func() int {
+		executions, err := engine.ListExecutions("task", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return len(executions)
+	}
+
+	engine.Start()
+
+	waitFor(t, func() bool { return schedulerStatus() == schedulerPaused }, "expected follower scheduler to be paused")
+	time.Sleep(20 * time.Millisecond)
+	if got := executions(); got != 0 {
+		t.Errorf("expected follower to execute nothing, got %d executions", got)
+	}
+	if engine.IsLeader() {
+		t.Error("expected engine to be a follower")
+	}
+
+	elector.leader.Store(true)
+	waitFor(t, func() bool { return schedulerStatus() == schedulerRunning }, "expected leader scheduler to resume")
+	waitFor(t, func() bool { return executions() > 0 }, "expected leader to execute the task")
+
+	elector.leader.Store(false)
+	waitFor(t, func() bool { return schedulerStatus() == schedulerPaused }, "expected scheduler to pause on leadership loss")
+
+	elector.leader.Store(true)
+	waitFor(t, engine.IsLeader, "expected engine to regain leadership")
+
+	if err := engine.Shutdown(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !elector.released.Load() {
+		t.Error("expected leadership to be released on shutdown")
+	}
+	if engine.IsLeader() {
+		t.Error("expected engine to be a follower after shutdown")
+	}
+
+}
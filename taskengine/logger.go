@@ -3,6 +3,9 @@ package taskengine
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Logger interface {
@@ -14,6 +17,10 @@ type Logger interface {
 
 	Error(msg string)
 	Errorf(format string, args ...any)
+
+	// With returns a logger that attaches the given key/value pairs, as in
+	// log/slog, to every message.
+	With(args ...any) Logger
 }
 
 type LoggerFactory func(module string) Logger
@@ -22,19 +29,62 @@ type stdLogger struct {
 	prefixInfo  string
 	prefixWarn  string
 	prefixError string
+
+	// attrs is appended to every message as " key=value" pairs.
+	attrs string
+}
+
+func (l *stdLogger) Info(msg string) { log.Println(l.prefixInfo + msg + l.attrs) }
+
+func (l *stdLogger) Infof(format string, args ...any) {
+	l.Info(fmt.Sprintf(format, args...))
 }
 
-func (l *stdLogger) Info(msg string) { log.Println(l.prefixInfo + msg) }
+func (l *stdLogger) Warn(msg string) { log.Println(l.prefixWarn + msg + l.attrs) }
 
-func (l *stdLogger) Infof(format string, args ...any) { log.Printf(l.prefixInfo+format, args...) }
+func (l *stdLogger) Warnf(format string, args ...any) {
+	l.Warn(fmt.Sprintf(format, args...))
+}
 
-func (l *stdLogger) Warn(msg string) { log.Println(l.prefixWarn + msg) }
+func (l *stdLogger) Error(msg string) { log.Println(l.prefixError + msg + l.attrs) }
 
-func (l *stdLogger) Warnf(format string, args ...any) { log.Printf(l.prefixWarn+format, args...) }
+func (l *stdLogger) Errorf(format string, args ...any) {
+	l.Error(fmt.Sprintf(format, args...))
+}
 
-func (l *stdLogger) Error(msg string) { log.Println(l.prefixError + msg) }
+func (l *stdLogger) With(args ...any) Logger {
+	with := *l
+	with.attrs += formatAttrs(args)
+	return &with
+}
 
-func (l *stdLogger) Errorf(format string, args ...any) { log.Printf(l.prefixError+format, args...) }
+// formatAttrs renders key/value pairs the way slog's text handler does. A
+// value without a key is reported under !BADKEY.
+func formatAttrs(args []any) string {
+	var b strings.Builder
+	for i := 0; i < len(args); i += 2 {
+		key, value := fmt.Sprint(args[i]), any(nil)
+		if i+1 < len(args) {
+			value = args[i+1]
+		} else {
+			key, value = "!BADKEY", args[i]
+		}
+
+		var text string
+		switch v := value.(type) {
+		case time.Time:
+			text = v.Format(time.RFC3339Nano)
+		default:
+			text = fmt.Sprint(v)
+		}
+		if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+
+		b.WriteString(" " + key + "=" + text)
+	}
+	return b.String()
+}
 
 func DefaultLoggerFactory(module string) Logger {
 	if module == "" {
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultLoggerFactory(t *testing.T) {
@@ -186,3 +187,30 @@ func TestStdLoggerMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestStdLoggerWith(t *testing.T) {
+	var buf bytes.Buffer
+	oldOutput := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(oldOutput)
+
+	tick := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger := DefaultLoggerFactory("test").With("task", "a", "tick", tick)
+	logger.With("note", "two words", "dangling").Warnf("warning %d", 1)
+	logger.Info("plain")
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"attributes", `[test][WARN] warning 1 task=a tick=2024-01-02T03:04:05Z note="two words" !BADKEY=dangling`},
+		{"parent unchanged", `[test][INFO] plain task=a tick=2024-01-02T03:04:05Z` + "\n"},
+	}
+
+	output := buf.String()
+	for _, tc := range tests {
+		if !strings.Contains(output, tc.expected) {
+			t.Errorf("%s: expected output to contain %q, got %q", tc.name, tc.expected, output)
+		}
+	}
+}
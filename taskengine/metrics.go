@@ -0,0 +1,61 @@
+package taskengine
+
+import (
+	"context"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// MetricsSink receives the measurements of an engine. Executions are
+// reported as they are saved; queue, in-flight and scheduler gauges are
+// sampled periodically.
+type MetricsSink interface {
+	// ObserveExecution records a saved execution. lag is how long after
+	// its tick the execution started.
+	ObserveExecution(task string, status store.ExecutionStatus, duration, lag time.Duration)
+	SetQueue(task string, size, capacity int)
+	SetInFlight(task string, inFlight int)
+	SetSchedulerState(task string, state string)
+}
+
+type nopMetricsSink struct{}
+
+func (nopMetricsSink) ObserveExecution(string, store.ExecutionStatus, time.Duration, time.Duration) {
+}
+
+func (nopMetricsSink) SetQueue(string, int, int) {}
+
+func (nopMetricsSink) SetInFlight(string, int) {}
+
+func (nopMetricsSink) SetSchedulerState(string, string) {}
+
+func (e *Engine) runMetrics(ctx context.Context) {
+	ticker := time.NewTicker(e.metricsInterval)
+	defer ticker.Stop()
+
+	for {
+		e.sampleMetrics()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Engine) sampleMetrics() {
+	e.mu.Lock()
+	supervisors := make(map[string]*WorkerSupervisor, len(e.supervisors))
+	for name, s := range e.supervisors {
+		supervisors[name] = s
+	}
+	e.mu.Unlock()
+
+	for name, s := range supervisors {
+		e.metrics.SetQueue(name, s.dispatcher.Size(), s.dispatcher.Capacity())
+		e.metrics.SetInFlight(name, s.InFlight())
+		e.metrics.SetSchedulerState(name, s.SchedulerStatus().String())
+	}
+}
@@ -0,0 +1,77 @@
+package taskengine
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+type mockMetricsSink struct {
+	mu sync.Mutex
+
+	statuses  []store.ExecutionStatus
+	capacity  map[string]int
+	scheduler map[string]string
+}
+
+func (m *mockMetricsSink) ObserveExecution(
+	task string, status store.ExecutionStatus, duration, lag time.Duration,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func (m *mockMetricsSink) SetQueue(task string, size, capacity int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.capacity[task] = capacity
+}
+
+func (m *mockMetricsSink) SetInFlight(task string, inFlight int) {}
+
+func (m *mockMetricsSink) SetSchedulerState(task string, state string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scheduler[task] = state
+}
+
+func TestEngineMetrics(t *testing.T) {
+	sink := &mockMetricsSink{capacity: map[string]int{}, scheduler: map[string]string{}}
+	engine, err := New(
+		memory.NewStore(),
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+		WithMetrics(sink, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	registerTestTask(t, engine, "task", trigger)
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	if err := engine.RunNow("task"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	waitFor(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.statuses) == 1 && sink.scheduler["task"] == "running"
+	}, "expected the execution and gauges to be reported")
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.statuses[0] != store.ExecutionStatusSuccess {
+		t.Errorf("expected success, got %s", sink.statuses[0])
+	}
+	if got := sink.capacity["task"]; got != 5 {
+		t.Errorf("expected queue capacity 5, got %d", got)
+	}
+}
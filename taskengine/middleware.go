@@ -0,0 +1,177 @@
+package taskengine
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Middleware wraps a job with behavior that runs around every call to it.
+type Middleware = func(Job) Job
+
+// middlewareChain holds the engine and task middlewares of a task and the
+// job they compose into, so that executions never wait on Use.
+type middlewareChain struct {
+	mu sync.Mutex
+
+	engine []Middleware
+	task   []Middleware
+
+	job atomic.Pointer[Job]
+}
+
+// compose wraps job so that engine middlewares run outside task
+// middlewares, and within each level the first middleware added runs
+// outermost.
+func (c *middlewareChain) compose(job Job) {
+	middleware := make([]Middleware, 0, len(c.engine)+len(c.task))
+	middleware = append(middleware, c.engine...)
+	middleware = append(middleware, c.task...)
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		job = middleware[i](job)
+	}
+	c.job.Store(&job)
+}
+
+// Use adds middleware around the job of the task. Task middlewares run
+// inside the middlewares of the engine.
+func (t *Task) Use(middleware ...Middleware) {
+	t.middleware.mu.Lock()
+	defer t.middleware.mu.Unlock()
+
+	t.middleware.task = append(t.middleware.task, middleware...)
+	t.middleware.compose(t.job)
+}
+
+func (t *Task) setEngineMiddleware(middleware []Middleware) {
+	t.middleware.mu.Lock()
+	defer t.middleware.mu.Unlock()
+
+	t.middleware.engine = middleware
+	t.middleware.compose(t.job)
+}
+
+// wrappedJob returns the job with its middlewares applied.
+func (t *Task) wrappedJob() Job {
+	if job := t.middleware.job.Load(); job != nil {
+		return *job
+	}
+	return t.job
+}
+
+// Use adds middleware around the job of every task, registered or not.
+// Engine middlewares run outside the middlewares of each task.
+func (e *Engine) Use(middleware ...Middleware) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.middleware = append(e.middleware[:len(e.middleware):len(e.middleware)], middleware...)
+	for _, s := range e.supervisors {
+		s.worker.task.setEngineMiddleware(e.middleware)
+	}
+	for _, w := range e.workflows {
+		for _, task := range w.tasks {
+			task.setEngineMiddleware(e.middleware)
+		}
+	}
+}
+
+// DefaultMiddleware returns the middlewares engines and tasks start with:
+// the execution logs of the engine around RecoveryMiddleware.
+// WithMiddleware replaces them.
+func DefaultMiddleware() []Middleware {
+	return []Middleware{executionLoggingMiddleware(), RecoveryMiddleware()}
+}
+
+// executionLoggingMiddleware logs how every execution of the task went
+// through the task logger, so that its lines stay out of captured logs.
+func executionLoggingMiddleware() Middleware {
+	return func(next Job) Job {
+		return func(ctx *Context) error {
+			logger := ctx.taskLogger
+			logger.Infof("Executing Task '%s'", ctx.TaskName())
+
+			err := next(ctx)
+
+			var panicErr *PanicError
+			switch {
+			case errors.As(err, &panicErr):
+				logger.Errorf("PANIC in Task '%s' job: %v", ctx.TaskName(), panicErr.Value)
+			case err != nil:
+				logger.Errorf("Task '%s' failed: %v", ctx.TaskName(), err)
+			default:
+				logger.Infof("Task '%s' completed successfully", ctx.TaskName())
+			}
+			return err
+		}
+	}
+}
+
+// PanicError is returned by RecoveryMiddleware for a job that panicked. The
+// execution is saved as a panic with the stack trace in its error message.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("PANIC: %v", e.Value) }
+
+// RecoveryMiddleware turns a panic of the job into a PanicError carrying the
+// stack trace of the panic. Tasks recover panics that reach them anyway, but
+// only this middleware records where the panic happened.
+func RecoveryMiddleware() Middleware {
+	return func(next Job) Job {
+		return func(ctx *Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// TimingMiddleware calls observe with how long every call to the job took
+// and the error it returned. A nil observe logs the duration instead.
+func TimingMiddleware(observe func(ctx *Context, duration time.Duration, err error)) Middleware {
+	if observe == nil {
+		observe = func(ctx *Context, duration time.Duration, err error) {
+			ctx.Logger().With("duration", duration).Infof(
+				"Job of task '%s' took %s", ctx.TaskName(), duration,
+			)
+		}
+	}
+
+	return func(next Job) Job {
+		return func(ctx *Context) error {
+			start := time.Now()
+			err := next(ctx)
+			observe(ctx, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// LoggingMiddleware logs through the job logger when the job starts and how
+// it ended.
+func LoggingMiddleware() Middleware {
+	return func(next Job) Job {
+		return func(ctx *Context) error {
+			logger := ctx.Logger()
+			logger.Infof("Job of task '%s' started", ctx.TaskName())
+
+			err := next(ctx)
+			if err != nil {
+				logger.Errorf("Job of task '%s' failed: %v", ctx.TaskName(), err)
+				return err
+			}
+			logger.Infof("Job of task '%s' succeeded", ctx.TaskName())
+			return nil
+		}
+	}
+}
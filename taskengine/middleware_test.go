@@ -0,0 +1,169 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func recordingMiddleware(calls *[]string, name string) Middleware {
+	return func(next Job) Job {
+		return func(ctx *Context) error {
+			*calls = append(*calls, name)
+			return next(ctx)
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	engine := newTestEngine(t)
+
+	var calls []string
+	engine.Use(recordingMiddleware(&calls, "engine1"))
+
+	task, err := NewTask("task", func(ctx *Context) error {
+		calls = append(calls, "job")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task.Use(recordingMiddleware(&calls, "task1"), recordingMiddleware(&calls, "task2"))
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine.Use(recordingMiddleware(&calls, "engine2"))
+
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	expected := []string{"engine1", "engine2", "task1", "task2", "job"}
+	if strings.Join(calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	var recovered any
+	task, s := newTestTask(t, func(ctx *Context) error { panic("boom") },
+		WithRetry(RetryPolicy{MaxAttempts: 3}),
+		WithOnPanic(func(ctx *Context, info *store.ExecutionInfo, value any) error {
+			recovered = value
+			return nil
+		}),
+	)
+	task.Use(RecoveryMiddleware())
+
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	executions := listExecutions(t, s)
+	if len(executions) != 1 {
+		t.Fatalf("expected a single execution, got %d", len(executions))
+	}
+	execution := executions[0]
+	if execution.Status != store.ExecutionStatusPanic {
+		t.Errorf("expected status %s, got %s", store.ExecutionStatusPanic, execution.Status)
+	}
+	if !strings.HasPrefix(execution.ErrorMsg, "PANIC: boom\n") || !strings.Contains(execution.ErrorMsg, "goroutine") {
+		t.Errorf("expected panic message with stack trace, got %q", execution.ErrorMsg)
+	}
+	if recovered != "boom" {
+		t.Errorf("expected OnPanic hook with boom, got %v", recovered)
+	}
+}
+
+func TestPanicRecoveredWithoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []EngineOption
+		stack   bool
+	}{
+		{"default", nil, true},
+		{"replaced", []EngineOption{WithMiddleware()}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := memory.NewStore()
+			engine, err := New(ms, append(tc.options,
+				WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+			)...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var recovered any
+			task, err := NewTask("task", func(ctx *Context) error { panic("boom") },
+				WithOnPanic(func(ctx *Context, info *store.ExecutionInfo, value any) error {
+					recovered = value
+					return nil
+				}),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			trigger, _ := NewIntervalTrigger(time.Hour, false)
+			if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+			executions := listExecutions(t, ms)
+			if len(executions) != 1 || executions[0].Status != store.ExecutionStatusPanic {
+				t.Fatalf("expected a single panic execution, got %d executions", len(executions))
+			}
+			if stack := strings.Contains(executions[0].ErrorMsg, "goroutine"); stack != tc.stack {
+				t.Errorf("expected stack trace %v, got %q", tc.stack, executions[0].ErrorMsg)
+			}
+			if recovered != "boom" {
+				t.Errorf("expected OnPanic hook with boom, got %v", recovered)
+			}
+		})
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	jobErr := errors.New("failed")
+	var gotDuration time.Duration
+	var gotErr error
+
+	task, _ := newTestTask(t, func(ctx *Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return jobErr
+	})
+	task.Use(TimingMiddleware(func(ctx *Context, duration time.Duration, err error) {
+		gotDuration, gotErr = duration, err
+	}))
+
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	if gotDuration < 10*time.Millisecond {
+		t.Errorf("expected duration of at least 10ms, got %s", gotDuration)
+	}
+	if !errors.Is(gotErr, jobErr) {
+		t.Errorf("expected job error, got %v", gotErr)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	task, s := newTestTask(t, noopJob, WithLogCapture(0))
+	task.Use(LoggingMiddleware())
+
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	executions := listExecutions(t, s)
+	if len(executions) != 1 {
+		t.Fatalf("expected a single execution, got %d", len(executions))
+	}
+	logs := executions[0].Logs
+	if !strings.Contains(logs, "Job of task 'task' started") || !strings.Contains(logs, "Job of task 'task' succeeded") {
+		t.Errorf("expected start and success lines in logs, got %q", logs)
+	}
+}
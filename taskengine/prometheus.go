@@ -0,0 +1,240 @@
+package taskengine
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the duration
+// and schedule lag histograms of a PrometheusSink.
+var DefaultDurationBuckets = []float64{
+	0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600,
+}
+
+var schedulerStates = []string{
+	schedulerIdle.String(), schedulerPaused.String(), schedulerRunning.String(),
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(buckets []float64, value float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+type executionKey struct {
+	task   string
+	status store.ExecutionStatus
+}
+
+// PrometheusSink is a MetricsSink that serves what it receives in the
+// Prometheus text exposition format.
+type PrometheusSink struct {
+	mu sync.Mutex
+
+	buckets []float64
+
+	executions     map[executionKey]uint64
+	durations      map[string]*histogram
+	lags           map[string]*histogram
+	queueSizes     map[string]int
+	queueCaps      map[string]int
+	inFlight       map[string]int
+	schedulerState map[string]string
+}
+
+func (p *PrometheusSink) ObserveExecution(
+	task string, status store.ExecutionStatus, duration, lag time.Duration,
+) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.executions[executionKey{task: task, status: status}]++
+
+	// Skipped executions never ran, so they have no duration or lag.
+	if status == store.ExecutionStatusSkipped {
+		return
+	}
+	if p.durations[task] == nil {
+		p.durations[task] = &histogram{}
+		p.lags[task] = &histogram{}
+	}
+	p.durations[task].observe(p.buckets, duration.Seconds())
+	p.lags[task].observe(p.buckets, lag.Seconds())
+}
+
+func (p *PrometheusSink) SetQueue(task string, size, capacity int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queueSizes[task] = size
+	p.queueCaps[task] = capacity
+}
+
+func (p *PrometheusSink) SetInFlight(task string, inFlight int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight[task] = inFlight
+}
+
+func (p *PrometheusSink) SetSchedulerState(task string, state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.schedulerState[task] = state
+}
+
+func (p *PrometheusSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format.
+func (p *PrometheusSink) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+
+	header(&b, "taskengine_executions_total", "counter", "Saved executions by task and status.")
+	keys := make([]executionKey, 0, len(p.executions))
+	for key := range p.executions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].task != keys[j].task {
+			return keys[i].task < keys[j].task
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		sample(&b, "taskengine_executions_total",
+			labels("status", string(key.status), "task", key.task),
+			float64(p.executions[key]))
+	}
+
+	p.writeHistograms(&b, "taskengine_execution_duration_seconds",
+		"Duration of executions that ran.", p.durations)
+	p.writeHistograms(&b, "taskengine_schedule_lag_seconds",
+		"Delay between the tick of an execution and its start.", p.lags)
+
+	writeGauges(&b, "taskengine_dispatcher_queue_size",
+		"Ticks waiting in the dispatcher.", p.queueSizes)
+	writeGauges(&b, "taskengine_dispatcher_queue_capacity",
+		"Capacity of the dispatcher.", p.queueCaps)
+	writeGauges(&b, "taskengine_inflight_executions",
+		"Executions currently running.", p.inFlight)
+
+	header(&b, "taskengine_scheduler_state", "gauge", "Current scheduler state, 1 for the active state.")
+	for _, task := range sortedKeys(p.schedulerState) {
+		for _, state := range schedulerStates {
+			value := 0.0
+			if p.schedulerState[task] == state {
+				value = 1
+			}
+			sample(&b, "taskengine_scheduler_state",
+				labels("state", state, "task", task), value)
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (p *PrometheusSink) writeHistograms(
+	b *strings.Builder, name, help string, histograms map[string]*histogram,
+) {
+	header(b, name, "histogram", help)
+	for _, task := range sortedKeys(histograms) {
+		h := histograms[task]
+		for i, bound := range p.buckets {
+			sample(b, name+"_bucket",
+				labels("le", formatFloat(bound), "task", task),
+				float64(h.counts[i]))
+		}
+		sample(b, name+"_bucket", labels("le", "+Inf", "task", task), float64(h.count))
+		sample(b, name+"_sum", labels("task", task), h.sum)
+		sample(b, name+"_count", labels("task", task), float64(h.count))
+	}
+}
+
+func writeGauges(b *strings.Builder, name, help string, values map[string]int) {
+	header(b, name, "gauge", help)
+	for _, task := range sortedKeys(values) {
+		sample(b, name, labels("task", task), float64(values[task]))
+	}
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(b *strings.Builder, name, labels string, value float64) {
+	fmt.Fprintf(b, "%s{%s} %s\n", name, labels, formatFloat(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats alternating label names and values.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewPrometheusSink returns a sink whose histograms use buckets, or
+// DefaultDurationBuckets when none are given.
+func NewPrometheusSink(buckets ...float64) *PrometheusSink {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusSink{
+		buckets:        buckets,
+		executions:     make(map[executionKey]uint64),
+		durations:      make(map[string]*histogram),
+		lags:           make(map[string]*histogram),
+		queueSizes:     make(map[string]int),
+		queueCaps:      make(map[string]int),
+		inFlight:       make(map[string]int),
+		schedulerState: make(map[string]string),
+	}
+}
@@ -0,0 +1,56 @@
+package taskengine
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+func TestPrometheusSink(t *testing.T) {
+	sink := NewPrometheusSink(1, 10)
+
+	sink.ObserveExecution("task", store.ExecutionStatusSuccess, 500*time.Millisecond, 2*time.Second)
+	sink.ObserveExecution("task", store.ExecutionStatusError, 5*time.Second, 20*time.Second)
+	sink.ObserveExecution("task", store.ExecutionStatusSkipped, 0, time.Hour)
+	sink.SetQueue("task", 3, 10)
+	sink.SetInFlight("task", 2)
+	sink.SetSchedulerState("task", "paused")
+	sink.SetQueue(`quoted "task"`, 0, 1)
+
+	recorder := httptest.NewRecorder()
+	sink.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("expected Prometheus text content type, got %q", got)
+	}
+
+	body := recorder.Body.String()
+	expected := []string{
+		"# TYPE taskengine_executions_total counter",
+		`taskengine_executions_total{status="error",task="task"} 1`,
+		`taskengine_executions_total{status="skipped",task="task"} 1`,
+		`taskengine_executions_total{status="success",task="task"} 1`,
+		"# TYPE taskengine_execution_duration_seconds histogram",
+		`taskengine_execution_duration_seconds_bucket{le="1",task="task"} 1`,
+		`taskengine_execution_duration_seconds_bucket{le="10",task="task"} 2`,
+		`taskengine_execution_duration_seconds_bucket{le="+Inf",task="task"} 2`,
+		`taskengine_execution_duration_seconds_sum{task="task"} 5.5`,
+		`taskengine_execution_duration_seconds_count{task="task"} 2`,
+		`taskengine_schedule_lag_seconds_bucket{le="10",task="task"} 1`,
+		`taskengine_schedule_lag_seconds_bucket{le="+Inf",task="task"} 2`,
+		`taskengine_dispatcher_queue_size{task="task"} 3`,
+		`taskengine_dispatcher_queue_capacity{task="task"} 10`,
+		`taskengine_dispatcher_queue_size{task="quoted \"task\""} 0`,
+		`taskengine_inflight_executions{task="task"} 2`,
+		`taskengine_scheduler_state{state="paused",task="task"} 1`,
+		`taskengine_scheduler_state{state="running",task="task"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected output to contain %q, got:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package taskengine
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns how long to wait after the given failed attempt, starting
+// at 1, before the next one.
+type Backoff func(attempt int) time.Duration
+
+func FixedBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration { return delay }
+}
+
+// ExponentialBackoff waits initial after the first attempt and multiplies the
+// delay by multiplier after each following one, never exceeding max when max
+// is positive.
+func ExponentialBackoff(initial, max time.Duration, multiplier float64) Backoff {
+	if multiplier < 1 {
+		multiplier = 2 // Default multiplier
+	}
+	return func(attempt int) time.Duration {
+		delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+		if max > 0 && delay > float64(max) {
+			return max
+		}
+		return time.Duration(delay)
+	}
+}
+
+// RetryPolicy describes how a task reacts to a job returning an error.
+// Panics are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first one included.
+	MaxAttempts int
+	// Backoff computes the delay between attempts; no delay when nil.
+	Backoff Backoff
+	// Jitter randomizes each delay by up to the given fraction of it,
+	// e.g. 0.2 yields delays between 80% and 120% of the backoff.
+	Jitter float64
+	// MaxElapsedTime stops retrying once the next attempt would start later
+	// than this long after the first one. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// Retryable reports whether an error is worth retrying; every error is
+	// when nil.
+	Retryable func(err error) bool
+}
+
+// nextDelay reports whether another attempt should follow the failed one and
+// how long to wait before it.
+func (p *RetryPolicy) nextDelay(attempt int, err error, elapsed time.Duration) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if p.Retryable != nil && !p.Retryable(err) {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if p.Backoff != nil {
+		delay = p.Backoff(attempt)
+	}
+	if p.Jitter > 0 && delay > 0 {
+		delta := p.Jitter * float64(delay)
+		delay = time.Duration(float64(delay) - delta + rand.Float64()*2*delta)
+	}
+
+	if p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return 0, false
+	}
+	return delay, true
+}
@@ -0,0 +1,90 @@
+package taskengine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFixedBackoff(t *testing.T) {
+	backoff := FixedBackoff(time.Second)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if got := backoff(attempt); got != time.Second {
+			t.Errorf("expected delay %s for attempt %d, got %s", time.Second, attempt, got)
+		}
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second, 2)
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tc := range tests {
+		if got := backoff(tc.attempt); got != tc.expected {
+			t.Errorf("expected delay %s for attempt %d, got %s", tc.expected, tc.attempt, got)
+		}
+	}
+}
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+
+	policy := &RetryPolicy{
+		MaxAttempts:    3,
+		Backoff:        FixedBackoff(time.Second),
+		MaxElapsedTime: 10 * time.Second,
+		Retryable:      func(err error) bool { return errors.Is(err, errTemporary) },
+	}
+
+	tests := []struct {
+		name      string
+		policy    *RetryPolicy
+		attempt   int
+		err       error
+		elapsed   time.Duration
+		wantRetry bool
+	}{
+		{"nil policy", nil, 1, errTemporary, 0, false},
+		{"retryable error", policy, 1, errTemporary, 0, true},
+		{"last attempt", policy, 3, errTemporary, 0, false},
+		{"not retryable", policy, 1, errPermanent, 0, false},
+		{"elapsed time exceeded", policy, 2, errTemporary, 9500 * time.Millisecond, false},
+		{"nil predicate retries everything", &RetryPolicy{MaxAttempts: 2}, 1, errPermanent, 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, retry := tc.policy.nextDelay(tc.attempt, tc.err, tc.elapsed)
+			if retry != tc.wantRetry {
+				t.Fatalf("expected retry=%v, got %v", tc.wantRetry, retry)
+			}
+			if retry && tc.policy.Backoff != nil && delay != time.Second {
+				t.Errorf("expected delay %s, got %s", time.Second, delay)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 2, Backoff: FixedBackoff(time.Second), Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay, _ := policy.nextDelay(1, errors.New("boom"), 0)
+		if delay < 500*time.Millisecond || delay > 1500*time.Millisecond {
+			t.Fatalf("expected delay within 50%% of 1s, got %s", delay)
+		}
+	}
+}
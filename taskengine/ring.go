@@ -0,0 +1,59 @@
+package taskengine
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ringReplicas is the number of points each node gets on the ring, which
+// evens out how many keys every node owns.
+const ringReplicas = 64
+
+// hashRing assigns keys to nodes by consistent hashing: when a node joins or
+// leaves, only the keys it gains or loses change owner.
+type hashRing struct {
+	points []uint64
+	owners map[uint64]string
+}
+
+// hashKey hashes with FNV-1a and then mixes the result, since FNV alone
+// spreads keys that differ in a single character poorly over the ring.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func (r *hashRing) owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+func newHashRing(nodes []string) *hashRing {
+	r := &hashRing{owners: make(map[uint64]string, len(nodes)*ringReplicas)}
+	for _, node := range nodes {
+		for i := 0; i < ringReplicas; i++ {
+			point := hashKey(node + "#" + strconv.Itoa(i))
+			r.points = append(r.points, point)
+			r.owners[point] = node
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
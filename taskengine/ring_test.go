@@ -0,0 +1,39 @@
+package taskengine
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHashRing(t *testing.T) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("task-%d", i)
+	}
+
+	if got := newHashRing(nil).owner("task"); got != "" {
+		t.Errorf("expected no owner on an empty ring, got %q", got)
+	}
+
+	before := newHashRing([]string{"node-a", "node-b", "node-c"})
+	after := newHashRing([]string{"node-a", "node-c"})
+
+	owned := make(map[string]int)
+	for _, key := range keys {
+		owner := before.owner(key)
+		owned[owner]++
+
+		if owner != "node-b" && after.owner(key) != owner {
+			t.Errorf("expected %s to stay on %s, got %s", key, owner, after.owner(key))
+		}
+		if got := after.owner(key); got == "node-b" {
+			t.Errorf("expected %s to leave the removed node", key)
+		}
+	}
+
+	for _, node := range []string{"node-a", "node-b", "node-c"} {
+		if owned[node] < len(keys)/6 {
+			t.Errorf("expected %s to own a fair share of keys, got %d", node, owned[node])
+		}
+	}
+}
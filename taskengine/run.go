@@ -0,0 +1,60 @@
+package taskengine
+
+import "time"
+
+type RunOption func(*Tick)
+
+// WithParams sets the parameters readable through Context.Params.
+func WithParams(params map[string]any) RunOption {
+	return func(t *Tick) {
+		t.params = params
+	}
+}
+
+func WithParam(key string, value any) RunOption {
+	return func(t *Tick) {
+		if t.params == nil {
+			t.params = make(map[string]any)
+		}
+		t.params[key] = value
+	}
+}
+
+// RunNow enqueues an execution of a running task outside of its schedule.
+// The execution goes through the task dispatcher, so the worker policy still
+// applies, and it is saved as manual. Its last tick is the tick of the most
+// recent scheduled execution and its current tick is the time of the call.
+func (e *Engine) RunNow(name string, options ...RunOption) error {
+	e.mu.Lock()
+	s, exists := e.supervisors[name]
+	e.mu.Unlock()
+
+	if !exists {
+		return ErrorTaskNotFound
+	}
+
+	if s.Status() != workerSupervisorRunning {
+		return ErrorTaskNotRunning
+	}
+
+	lastTick, err := e.store.GetLastTick(name)
+	if err != nil {
+		return err
+	}
+
+	tick := &Tick{
+		lastTick:    lastTick,
+		currentTick: time.Now(),
+		manual:      true,
+	}
+	for _, opt := range options {
+		opt(tick)
+	}
+
+	e.logger.Infof("Running task '%s' manually", name)
+	if err := s.enqueue(tick); err != nil {
+		return err
+	}
+	e.events.publish(tickEvent(EventTickDispatched, name, tick))
+	return nil
+}
@@ -0,0 +1,99 @@
+package taskengine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+func TestRunNow(t *testing.T) {
+	engine := newTestEngine(t)
+
+	params := make(chan map[string]any, 1)
+	task, err := NewTask("task", func(ctx *Context) error {
+		if !ctx.Manual() {
+			return errors.New("expected manual execution")
+		}
+		params <- ctx.Params()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := engine.RunNow("task"); !errors.Is(err, ErrorTaskNotRunning) {
+		t.Errorf("expected ErrorTaskNotRunning, got %v", err)
+	}
+	if err := engine.RunNow("missing"); !errors.Is(err, ErrorTaskNotFound) {
+		t.Errorf("expected ErrorTaskNotFound, got %v", err)
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	err = engine.RunNow("task", WithParams(map[string]any{"a": 1}), WithParam("b", "two"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-params:
+		if got["a"] != 1 || got["b"] != "two" {
+			t.Errorf("expected params a=1 and b=two, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected manual execution to run")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		executions, err := engine.ListExecutions("task", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(executions) == 1 {
+			if !executions[0].Manual || executions[0].Status != store.ExecutionStatusSuccess {
+				t.Errorf("expected successful manual execution, got %+v", executions[0].ExecutionInfo)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected manual execution to be saved")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunNowRejectedDuringShutdown(t *testing.T) {
+	engine := newTestEngine(t)
+
+	task, err := NewTask("task", func(ctx *Context) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	s := engine.supervisors["task"]
+	// Simulates RunNow passing its status check right before a Shutdown.
+	s.setAccepting(false)
+
+	if err := s.enqueue(&Tick{manual: true}); !errors.Is(err, ErrorTaskNotRunning) {
+		t.Errorf("expected ErrorTaskNotRunning, got %v", err)
+	}
+	if size := s.dispatcher.Size(); size != 0 {
+		t.Errorf("expected the tick not to be queued, got %d queued", size)
+	}
+}
@@ -2,8 +2,12 @@ package taskengine
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
 )
 
 type schedulerControlCommand int
@@ -21,14 +25,36 @@ const (
 	schedulerRunning
 )
 
+func (s schedulerState) String() string {
+	switch s {
+	case schedulerIdle:
+		return "idle"
+	case schedulerPaused:
+		return "paused"
+	case schedulerRunning:
+		return "running"
+	default:
+		return "unknown"
+	}
+}
+
 type Scheduler struct {
+	task    *Task
 	trigger Trigger
 
 	dispatcher Dispatcher
 
 	control chan schedulerControlCommand
 
+	// listener receives the events of an event trigger.
+	listener *subscription
+	unlisten func()
+
+	// occurrences is set when the trigger counts occurrences.
+	occurrences *occurrenceCounter
+
 	state          atomic.Value
+	nextTick       atomic.Value
 	catchUpEnabled bool
 	initLastTick   time.Time
 
@@ -37,11 +63,59 @@ type Scheduler struct {
 
 func (s *Scheduler) Status() schedulerState { return s.state.Load().(schedulerState) }
 
-func (s *Scheduler) Pause() { s.control <- schedulerPause }
+// NextTick returns the tick the scheduler is waiting for. When the scheduler
+// is not running, it is computed from the trigger instead.
+func (s *Scheduler) NextTick() time.Time {
+	if next := s.nextTick.Load().(time.Time); !next.IsZero() {
+		return next
+	}
 
-func (s *Scheduler) Resume() { s.control <- schedulerResume }
+	next, err := triggerNext(s.trigger, s.initLastTick, s.task.name, s.triggerStore())
+	if err != nil {
+		return time.Time{}
+	}
+	return next
+}
+
+func (s *Scheduler) Pause() { s.command(schedulerPause) }
+
+func (s *Scheduler) Resume() { s.command(schedulerResume) }
+
+// command hands cmd to the scheduler without blocking, so callers never
+// wait on a scheduler that stopped reading. A command the scheduler has not
+// received yet is replaced, as only the latest one matters.
+func (s *Scheduler) command(cmd schedulerControlCommand) {
+	for {
+		select {
+		case s.control <- cmd:
+			return
+		default:
+		}
 
-func (s *Scheduler) Run(ctx context.Context) error {
+		select {
+		case <-s.control:
+		default:
+		}
+	}
+}
+
+// dropCommands discards pause and resume commands the scheduler did not
+// receive before it stopped.
+func (s *Scheduler) dropCommands() {
+	for {
+		select {
+		case <-s.control:
+		default:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) error { return s.run(ctx, false) }
+
+// run starts the scheduler, optionally in the paused state so that a
+// follower keeps its scheduler warm without dispatching ticks.
+func (s *Scheduler) run(ctx context.Context, paused bool) error {
 	if s.state.Load().(schedulerState) != schedulerIdle {
 		s.logger.Error("Scheduler is already running or paused, cannot start again")
 		return nil
@@ -49,27 +123,82 @@ func (s *Scheduler) Run(ctx context.Context) error {
 
 	s.logger.Info("Starting Scheduler...")
 
-	lastTick := s.initLastTick
 	s.state.Store(schedulerRunning)
+	defer s.state.Store(schedulerIdle)
+	defer s.nextTick.Store(time.Time{})
+
+	if trigger, ok := s.trigger.(eventTrigger); ok {
+		return s.runEvents(ctx, trigger, paused)
+	}
+
+	lastTick := s.resync(s.initLastTick)
+	var pausedAt, resumedAt time.Time
+
+	// Past ticks skipped in a row are saved as one execution, so that a
+	// long downtime does not flood the store with skipped rows.
+	var missed int
+	var missedTick Tick
+	var missedReason store.SkipReason
+	flushMissed := func() {
+		if missed == 0 {
+			return
+		}
+		s.logger.With("tick", missedTick.currentTick).Warnf(
+			"Skipped %d past ticks up to %s (%s)",
+			missed, missedTick.currentTick.Format("2006-01-02 15:04:05"), missedReason,
+		)
+		s.task.skipMissed(&missedTick, missedReason, missed)
+		missed = 0
+	}
+
+	if paused {
+		s.logger.Info("Scheduler started paused")
+		s.state.Store(schedulerPaused)
+		s.task.events.publish(Event{Type: EventSchedulerPaused, Task: s.task.name})
+		pausedAt = time.Now()
+		goto Paused
+	}
 
 Run:
 	for {
 		now := time.Now()
-		nextTick, err := s.trigger.Next(lastTick)
+		nextTick, err := triggerNext(s.trigger, lastTick, s.task.name, s.triggerStore())
+		if errors.Is(err, ErrorNoMoreTicks) {
+			flushMissed()
+			if s.awaitPending(ctx) {
+				continue
+			}
+			if ctx.Err() != nil {
+				s.logger.Info("Scheduler shutdown complete")
+				return nil
+			}
+			s.logger.Info("Trigger has no more ticks, scheduler finished")
+			return err
+		}
 		if err != nil {
+			flushMissed()
 			s.logger.Errorf("Error getting next tick: %v", err)
 			return err
 		}
 
 		if nextTick.Before(now) && !s.catchUpEnabled {
-			s.logger.Warnf(
-				"Next tick %s is in the past, skipping",
-				nextTick.Format("2006-01-02 15:04:05"),
-			)
+			reason := store.SkipReasonStale
+			if !nextTick.Before(pausedAt) && nextTick.Before(resumedAt) {
+				reason = store.SkipReasonPaused
+			}
+			if reason != missedReason {
+				flushMissed()
+			}
+
+			missed++
+			missedReason = reason
+			missedTick = Tick{lastTick: lastTick, currentTick: nextTick}
 			lastTick = nextTick
 			continue
 		}
+		flushMissed()
 
+		s.nextTick.Store(nextTick)
 		select {
 		case <-time.After(time.Until(nextTick)):
 			tick := Tick{
@@ -77,22 +206,17 @@ Run:
 				currentTick: nextTick,
 			}
 
-			s.logger.Infof(
-				"Dispatching tick at %s",
-				nextTick.Format("2006-01-02 15:04:05"),
-			)
-			err := s.dispatcher.Enqueue(&tick)
-			if err != nil {
-				s.logger.Errorf("Error dispatching tick: %v", err)
+			if err := s.dispatch(&tick); err != nil {
 				return err
 			}
-
 			lastTick = nextTick
 		case cmd := <-s.control:
 			switch cmd {
 			case schedulerPause:
 				s.logger.Info("Scheduler paused")
 				s.state.Store(schedulerPaused)
+				s.task.events.publish(Event{Type: EventSchedulerPaused, Task: s.task.name})
+				pausedAt = time.Now()
 				break Run
 			default:
 			}
@@ -102,6 +226,7 @@ Run:
 		}
 	}
 
+Paused:
 	for {
 		select {
 		case cmd := <-s.control:
@@ -109,6 +234,9 @@ Run:
 			case schedulerResume:
 				s.logger.Info("Scheduler resumed")
 				s.state.Store(schedulerRunning)
+				s.task.events.publish(Event{Type: EventSchedulerResumed, Task: s.task.name})
+				resumedAt = time.Now()
+				lastTick = s.resync(lastTick)
 				goto Run
 			default:
 			}
@@ -119,7 +247,214 @@ Run:
 	}
 }
 
+// dispatch enqueues tick, recording it as skipped when the dispatcher queue
+// is full.
+func (s *Scheduler) dispatch(tick *Tick) error {
+	logger := s.logger.With("tick", tick.currentTick)
+	logger.Infof(
+		"Dispatching tick at %s",
+		tick.currentTick.Format("2006-01-02 15:04:05"),
+	)
+
+	if s.occurrences != nil {
+		s.occurrences.dispatched(tick)
+	}
+
+	err := s.dispatcher.Enqueue(tick)
+	if errors.Is(err, ErrorDispatcherFull) {
+		logger.Warnf(
+			"Dispatcher queue is full, skipping tick at %s",
+			tick.currentTick.Format("2006-01-02 15:04:05"),
+		)
+		s.task.skip(tick, store.SkipReasonQueueFull)
+		return nil
+	}
+	if err != nil {
+		logger.Errorf("Error dispatching tick: %v", err)
+		return err
+	}
+
+	s.task.events.publish(tickEvent(EventTickDispatched, s.task.name, tick))
+	return nil
+}
+
+// awaitPending waits for a pending tick to settle, since one that does not
+// run stops counting toward a limit. It reports whether the trigger must be
+// asked for a tick again.
+func (s *Scheduler) awaitPending(ctx context.Context) bool {
+	if s.occurrences == nil || !s.occurrences.hasPending() {
+		return false
+	}
+
+	select {
+	case <-s.occurrences.settled:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// triggerStore returns the store stateful triggers read, which includes the
+// dispatched ticks not counted yet in the occurrences of the task.
+func (s *Scheduler) triggerStore() store.Store {
+	if s.occurrences == nil || s.task.store == nil {
+		return s.task.store
+	}
+	return &pendingOccurrences{Store: s.task.store, counter: s.occurrences}
+}
+
+// occurrenceCounter counts the ticks of a task whose trigger counts
+// occurrences. A tick only counts once the worker runs it; until it is
+// settled, it is pending so that the scheduler does not dispatch past a
+// limit.
+type occurrenceCounter struct {
+	mu      sync.Mutex
+	pending int
+
+	// settled holds a signal whenever a pending tick was settled.
+	settled chan struct{}
+}
+
+func (c *occurrenceCounter) hasPending() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending > 0
+}
+
+func (c *occurrenceCounter) dispatched(tick *Tick) {
+	c.mu.Lock()
+	c.pending++
+	c.mu.Unlock()
+	tick.counter = c
+}
+
+// settle counts the occurrence of a pending tick when it ran. Both happen
+// under the lock, so that readers never see the tick twice or not at all.
+func (c *occurrenceCounter) settle(task string, s store.Store, ran bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	defer func() {
+		select {
+		case c.settled <- struct{}{}:
+		default:
+		}
+	}()
+
+	c.pending--
+	if !ran {
+		return nil
+	}
+	_, err := s.IncrementOccurrences(task)
+	return err
+}
+
+type pendingOccurrences struct {
+	store.Store
+	counter *occurrenceCounter
+}
+
+func (p *pendingOccurrences) GetOccurrences(task string) (int, error) {
+	p.counter.mu.Lock()
+	defer p.counter.mu.Unlock()
+
+	occurrences, err := p.Store.GetOccurrences(task)
+	if err != nil {
+		return 0, err
+	}
+	return occurrences + p.counter.pending, nil
+}
+
+// runEvents dispatches the ticks of an event trigger. Ticks fired while the
+// scheduler is paused are recorded as skipped.
+func (s *Scheduler) runEvents(ctx context.Context, trigger eventTrigger, paused bool) error {
+	s.listen()
+	defer func() { s.unlisten(); s.listener = nil }()
+
+	if paused {
+		s.logger.Info("Scheduler started paused")
+		s.state.Store(schedulerPaused)
+		s.task.events.publish(Event{Type: EventSchedulerPaused, Task: s.task.name})
+	}
+
+	for {
+		select {
+		case <-s.listener.queue.ready:
+			for {
+				event, ok := s.listener.queue.pop()
+				if !ok {
+					break
+				}
+				if err := s.fire(trigger, event, paused); err != nil {
+					return err
+				}
+			}
+		case cmd := <-s.control:
+			switch {
+			case cmd == schedulerPause && !paused:
+				s.logger.Info("Scheduler paused")
+				s.state.Store(schedulerPaused)
+				s.task.events.publish(Event{Type: EventSchedulerPaused, Task: s.task.name})
+				paused = true
+			case cmd == schedulerResume && paused:
+				s.logger.Info("Scheduler resumed")
+				s.state.Store(schedulerRunning)
+				s.task.events.publish(Event{Type: EventSchedulerResumed, Task: s.task.name})
+				paused = false
+			}
+		case <-ctx.Done():
+			s.logger.Info("Scheduler shutdown complete")
+			return nil
+		}
+	}
+}
+
+// fire dispatches the tick event triggers, if any, recording it as
+// skipped while the scheduler is paused.
+func (s *Scheduler) fire(trigger eventTrigger, event Event, paused bool) error {
+	tick, ok := trigger.tick(event)
+	if !ok {
+		return nil
+	}
+
+	if paused {
+		s.logger.With("tick", tick.currentTick).Warnf(
+			"Scheduler is paused, skipping tick at %s",
+			tick.currentTick.Format("2006-01-02 15:04:05"),
+		)
+		s.task.skip(tick, store.SkipReasonPaused)
+		return nil
+	}
+	return s.dispatch(tick)
+}
+
+// listen subscribes an event trigger to the engine events. Supervisors call
+// it before starting the scheduler goroutine, so that no event published
+// once the task started is missed. The subscription is lossless, since a
+// dropped event would silently lose a run of the task.
+func (s *Scheduler) listen() {
+	if trigger, ok := s.trigger.(eventTrigger); ok && s.listener == nil {
+		s.listener, s.unlisten = s.task.events.subscribeLossless(trigger.filter())
+	}
+}
+
+// resync returns the newest of lastTick and the last tick recorded in the
+// store, so ticks another engine handled while this one was stopped or
+// paused are not dispatched again.
+func (s *Scheduler) resync(lastTick time.Time) time.Time {
+	stored, err := s.task.store.GetLastTick(s.task.name)
+	if err != nil {
+		s.logger.Warnf("Could not resync last tick: %v", err)
+		return lastTick
+	}
+	if stored.After(lastTick) {
+		return stored
+	}
+	return lastTick
+}
+
 func newScheduler(
+	task *Task,
 	trigger Trigger,
 	dispatcher Dispatcher,
 	catchUpEnabled bool,
@@ -127,13 +462,18 @@ func newScheduler(
 	logger Logger,
 ) *Scheduler {
 	s := &Scheduler{
-		logger:         logger,
+		task:           task,
+		logger:         logger.With("task", task.name),
 		trigger:        trigger,
 		control:        make(chan schedulerControlCommand, 1),
 		dispatcher:     dispatcher,
 		catchUpEnabled: catchUpEnabled,
 		initLastTick:   initLastTick,
 	}
+	if countsOccurrences(trigger) {
+		s.occurrences = &occurrenceCounter{settled: make(chan struct{}, 1)}
+	}
 	s.state.Store(schedulerIdle)
+	s.nextTick.Store(time.Time{})
 	return s
 }
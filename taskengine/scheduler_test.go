@@ -0,0 +1,328 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func skipReasons(t *testing.T, s store.Store) map[store.SkipReason]int {
+	t.Helper()
+
+	reasons := make(map[store.SkipReason]int)
+	for _, execution := range listExecutions(t, s) {
+		if execution.Status != store.ExecutionStatusSkipped {
+			t.Errorf("expected skipped execution, got %s", execution.Status)
+		}
+		reasons[execution.SkipReason]++
+	}
+	return reasons
+}
+
+func TestSchedulerRecordsStaleTicks(t *testing.T) {
+	task, s := newTestTask(t, noopJob)
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	dispatcher := newDispatcher(10)
+
+	initLastTick := time.Now().Add(-3*time.Hour - 30*time.Minute)
+	scheduler := newScheduler(task, trigger, dispatcher, false, initLastTick, &mockLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); scheduler.Run(ctx) }()
+
+	waitFor(t, func() bool { return len(listExecutions(t, s)) == 1 }, "expected the skipped ticks to be recorded")
+	cancel()
+	<-done
+
+	executions := listExecutions(t, s)
+	if len(executions) != 1 {
+		t.Fatalf("expected the 3 stale ticks collapsed into 1 execution, got %d", len(executions))
+	}
+	skipped := executions[0]
+	if skipped.Status != store.ExecutionStatusSkipped || skipped.SkipReason != store.SkipReasonStale {
+		t.Errorf("expected stale skip, got %s (%s)", skipped.Status, skipped.SkipReason)
+	}
+	if skipped.MissedTicks != 3 {
+		t.Errorf("expected 3 missed ticks, got %d", skipped.MissedTicks)
+	}
+	if want := initLastTick.Add(3 * time.Hour); !skipped.Tick.Equal(want) {
+		t.Errorf("expected the latest stale tick %v, got %v", want, skipped.Tick)
+	}
+	if got := dispatcher.Size(); got != 0 {
+		t.Errorf("expected no dispatched ticks, got %d", got)
+	}
+}
+
+func TestSchedulerRecordsQueueFullTicks(t *testing.T) {
+	task, s := newTestTask(t, noopJob)
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	dispatcher := newDispatcher(1)
+
+	initLastTick := time.Now().Add(-3*time.Hour - 30*time.Minute)
+	scheduler := newScheduler(task, trigger, dispatcher, true, initLastTick, &mockLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); scheduler.Run(ctx) }()
+
+	waitFor(t, func() bool { return len(listExecutions(t, s)) == 2 }, "expected 2 skipped ticks")
+	cancel()
+	<-done
+
+	if got := skipReasons(t, s)[store.SkipReasonQueueFull]; got != 2 {
+		t.Errorf("expected 2 queue full skips, got %d", got)
+	}
+	if got := dispatcher.Size(); got != 1 {
+		t.Errorf("expected 1 dispatched tick, got %d", got)
+	}
+}
+
+func TestSchedulerRecordsPausedTicks(t *testing.T) {
+	task, s := newTestTask(t, noopJob)
+	trigger, _ := NewIntervalTrigger(10*time.Millisecond, false)
+	dispatcher := newDispatcher(100)
+	scheduler := newScheduler(task, trigger, dispatcher, false, time.Time{}, &mockLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); scheduler.Run(ctx) }()
+
+	waitFor(t, func() bool { return dispatcher.Size() > 0 }, "expected a dispatched tick")
+	scheduler.Pause()
+	waitFor(t, func() bool { return scheduler.Status() == schedulerPaused }, "expected scheduler to pause")
+	time.Sleep(50 * time.Millisecond)
+	scheduler.Resume()
+
+	waitFor(t, func() bool {
+		return skipReasons(t, s)[store.SkipReasonPaused] > 0
+	}, "expected ticks missed while paused to be recorded")
+	cancel()
+	<-done
+}
+
+func TestSchedulerCommandsDoNotBlock(t *testing.T) {
+	task, _ := newTestTask(t, noopJob)
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	scheduler := newScheduler(task, trigger, newDispatcher(1), false, time.Time{}, &mockLogger{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Pause()
+		scheduler.Resume()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected commands to a stopped scheduler not to block")
+	}
+
+	if cmd := <-scheduler.control; cmd != schedulerResume {
+		t.Errorf("expected the latest command to be pending, got %v", cmd)
+	}
+}
+
+func TestSchedulerAfterTaskTrigger(t *testing.T) {
+	engine := newTestEngine(t)
+
+	attempts := 0
+	upstream, err := NewTask("extract", func(ctx *Context) error {
+		if attempts++; attempts == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	}, WithRetry(RetryPolicy{MaxAttempts: 2}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interval, _ := NewIntervalTrigger(time.Hour, true)
+	if err := engine.RegisterTask(upstream, WorkerPolicySerial, interval, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ticks := make(chan [2]time.Time, 2)
+	downstream, err := NewTask("load", func(ctx *Context) error {
+		ticks <- [2]time.Time{ctx.LastTick(), ctx.CurrentTick()}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, _ := NewAfterTaskTrigger("extract")
+	if err := engine.RegisterTask(downstream, WorkerPolicySerial, after, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	var got [2]time.Time
+	select {
+	case got = <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("expected load to run after extract")
+	}
+
+	extract, err := engine.GetExecution("extract", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got[0].Equal(time.Time{}) || !got[1].Equal(extract.Tick) {
+		t.Errorf("expected ticks of extract (zero, %v), got %v", extract.Tick, got)
+	}
+
+	select {
+	case got = <-ticks:
+		t.Errorf("expected load to run once for the retried extract, ran again with %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSchedulerCompletesWithoutMoreTicks(t *testing.T) {
+	engine := newTestEngine(t)
+	events, unsubscribe := engine.Subscribe(EventFilter{Types: []EventType{EventSupervisorCompleted}})
+	defer unsubscribe()
+
+	now := time.Now()
+	trigger, _ := NewDatesTrigger([]time.Time{now.Add(10 * time.Millisecond), now.Add(20 * time.Millisecond)})
+
+	task, err := NewTask("task", func(ctx *Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.RegisterTask(task, WorkerPolicySerial, trigger, false, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatal("expected the task to complete")
+	}
+
+	info, err := engine.Task("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.SupervisorStatus != workerSupervisorCompleted {
+		t.Errorf("expected supervisor %s, got %s", workerSupervisorCompleted, info.SupervisorStatus)
+	}
+
+	executions, err := engine.ListExecutions("task", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executions) != 2 || executions[0].Status != store.ExecutionStatusSuccess {
+		t.Fatalf("expected both dates to be executed, got %d executions", len(executions))
+	}
+
+	if err := engine.StartTask("task"); !errors.Is(err, ErrorTaskCompleted) {
+		t.Errorf("expected ErrorTaskCompleted, got %v", err)
+	}
+	if err := engine.ShutdownTask("task"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.StartTask("task"); !errors.Is(err, ErrorTaskCompleted) {
+		t.Errorf("expected ErrorTaskCompleted after shutdown, got %v", err)
+	}
+	if info, _ := engine.Task("task"); info.SupervisorStatus != workerSupervisorCompleted {
+		t.Errorf("expected supervisor to stay %s, got %s", workerSupervisorCompleted, info.SupervisorStatus)
+	}
+	if executions, _ := engine.ListExecutions("task", nil); len(executions) != 2 {
+		t.Errorf("expected no new executions, got %d", len(executions))
+	}
+}
+
+func TestSchedulerLimitSurvivesRestart(t *testing.T) {
+	ms := memory.NewStore()
+	run := func() *Engine {
+		engine, err := New(ms, WithLoggerFactory(func(string) Logger { return &mockLogger{} }))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events, unsubscribe := engine.Subscribe(EventFilter{Types: []EventType{EventSupervisorCompleted}})
+		defer unsubscribe()
+
+		interval, _ := NewIntervalTrigger(10*time.Millisecond, true)
+		trigger, _ := Limit(interval, 3)
+		registerTestTask(t, engine, "task", trigger)
+
+		engine.Start()
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			t.Fatal("expected the task to complete")
+		}
+		return engine
+	}
+
+	engine := run()
+	if executions, _ := engine.ListExecutions("task", nil); len(executions) != 3 {
+		t.Fatalf("expected 3 executions, got %d", len(executions))
+	}
+	if err := engine.Shutdown(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A new engine over the same store resumes the count of occurrences.
+	engine = run()
+	defer engine.Shutdown()
+	if executions, _ := engine.ListExecutions("task", nil); len(executions) != 3 {
+		t.Errorf("expected the limit to survive the restart, got %d executions", len(executions))
+	}
+}
+
+func TestSchedulerLimitCountsTicksThatRan(t *testing.T) {
+	ms := memory.NewStore()
+	engine, err := New(ms, WithLoggerFactory(func(string) Logger { return &mockLogger{} }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events, unsubscribe := engine.Subscribe(EventFilter{Types: []EventType{EventSupervisorCompleted}})
+	defer unsubscribe()
+
+	task, err := NewTask("task", func(ctx *Context) error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interval, _ := NewIntervalTrigger(5*time.Millisecond, true)
+	trigger, _ := Limit(interval, 2)
+	if err := engine.RegisterTask(task, WorkerPolicySkipIfBusy, trigger, false, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	select {
+	case <-events:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the task to complete")
+	}
+
+	statuses := make(map[store.ExecutionStatus]int)
+	for _, execution := range listExecutions(t, ms) {
+		statuses[execution.Status]++
+	}
+	if statuses[store.ExecutionStatusSuccess] != 2 || statuses[store.ExecutionStatusSkipped] == 0 {
+		t.Errorf("expected 2 successes besides busy skips, got %v", statuses)
+	}
+	if occurrences, _ := ms.GetOccurrences("task"); occurrences != 2 {
+		t.Errorf("expected 2 occurrences, got %d", occurrences)
+	}
+}
@@ -0,0 +1,99 @@
+package taskengine
+
+import (
+	"context"
+	"time"
+)
+
+func (e *Engine) runMembership(ctx context.Context) {
+	ticker := time.NewTicker(e.heartbeatInterval)
+	defer ticker.Stop()
+
+	var lastHeartbeat time.Time
+	for {
+		nodes, err := e.heartbeat()
+		switch {
+		case err == nil:
+			lastHeartbeat = time.Now()
+			e.rebalance(nodes)
+		case time.Since(lastHeartbeat) > e.nodeTTL:
+			// The other nodes consider this one gone by now.
+			e.logger.Errorf("Lost cluster membership: %v", err)
+			e.rebalance(nil)
+		default:
+			e.logger.Warnf("Cluster heartbeat failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// heartbeat registers the engine as alive and returns the live nodes.
+func (e *Engine) heartbeat() ([]string, error) {
+	if err := e.store.Heartbeat(e.nodeID); err != nil {
+		return nil, err
+	}
+	return e.store.ListNodes(time.Now().Add(-e.nodeTTL))
+}
+
+// rebalance starts the tasks the hash ring newly assigns to this engine and
+// shuts down the ones it moved elsewhere. Tasks whose owner did not change
+// are left alone, so completed tasks and tasks stopped through ShutdownTask
+// stay stopped.
+func (e *Engine) rebalance(nodes []string) {
+	ring := newHashRing(nodes)
+
+	var start, stop []string
+	e.mu.Lock()
+	owned := make(map[string]bool, len(e.supervisors))
+	for name, s := range e.supervisors {
+		if ring.owner(name) == e.nodeID {
+			owned[name] = true
+		}
+		if owned[name] == e.owned[name] {
+			continue
+		}
+
+		status := s.Status()
+		if owned[name] && status == workerSupervisorIdle && !e.stopped[name] {
+			start = append(start, name)
+		} else if !owned[name] && status == workerSupervisorRunning {
+			stop = append(stop, name)
+		}
+	}
+	e.owned = owned
+	e.mu.Unlock()
+
+	for _, name := range stop {
+		e.logger.Infof("Task '%s' moved to node '%s'", name, ring.owner(name))
+		e.mu.Lock()
+		err := e.shutdownTask(name)
+		e.mu.Unlock()
+		if err != nil {
+			e.logger.Errorf("Failed to hand off task '%s': %v", name, err)
+		}
+	}
+	for _, name := range start {
+		e.logger.Infof("Task '%s' assigned to this node", name)
+		e.mu.Lock()
+		err := e.startTask(name)
+		if err != nil {
+			// Taking the task over is retried on the next heartbeat.
+			delete(e.owned, name)
+		}
+		e.mu.Unlock()
+		if err != nil {
+			e.logger.Errorf("Failed to take over task '%s': %v", name, err)
+		}
+	}
+}
+
+func (e *Engine) leaveCluster() {
+	if err := e.store.RemoveNode(e.nodeID); err != nil {
+		e.logger.Errorf("Failed to leave cluster: %v", err)
+	}
+}
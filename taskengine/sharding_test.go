@@ -0,0 +1,140 @@
+package taskengine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func newShardedEngine(t *testing.T, s store.Store, nodeID string, tasks int) *Engine {
+	t.Helper()
+
+	engine, err := New(
+		s,
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+		WithNodeID(nodeID),
+		WithSharding(5*time.Millisecond, 50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	for i := 0; i < tasks; i++ {
+		registerTestTask(t, engine, fmt.Sprintf("task-%d", i), trigger)
+	}
+	return engine
+}
+
+func runningTasks(engine *Engine) int {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	running := 0
+	for _, s := range engine.supervisors {
+		if s.Status() == workerSupervisorRunning {
+			running++
+		}
+	}
+	return running
+}
+
+func TestShardingRebalancesTasks(t *testing.T) {
+	const tasks = 20
+
+	s := memory.NewStore()
+	first := newShardedEngine(t, s, "node-a", tasks)
+	second := newShardedEngine(t, s, "node-b", tasks)
+
+	first.Start()
+	defer first.Shutdown()
+	waitFor(t, func() bool { return runningTasks(first) == tasks }, "expected a single node to run every task")
+
+	second.Start()
+	waitFor(t, func() bool {
+		a, b := runningTasks(first), runningTasks(second)
+		return a > 0 && b > 0 && a+b == tasks
+	}, "expected tasks to be spread over both nodes")
+
+	for i := 0; i < tasks; i++ {
+		name := fmt.Sprintf("task-%d", i)
+		a := first.supervisors[name].Status() == workerSupervisorRunning
+		b := second.supervisors[name].Status() == workerSupervisorRunning
+		if a == b {
+			t.Errorf("expected %s to run on exactly one node", name)
+		}
+	}
+
+	if err := second.Shutdown(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(t, func() bool { return runningTasks(first) == tasks }, "expected tasks to move back after a node left")
+
+	info, err := first.Task("task-0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.SchedulerStatus != schedulerRunning {
+		t.Errorf("expected restarted scheduler to be running, got %s", info.SchedulerStatus)
+	}
+}
+
+func TestShardingKeepsStoppedTasksStopped(t *testing.T) {
+	const tasks = 3
+
+	engine := newShardedEngine(t, memory.NewStore(), "node-a", tasks)
+	engine.Start()
+	defer engine.Shutdown()
+	waitFor(t, func() bool { return runningTasks(engine) == tasks }, "expected the node to run every task")
+
+	if err := engine.ShutdownTask("task-0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Several heartbeats go by without the ring changing.
+	time.Sleep(50 * time.Millisecond)
+	if status := engine.supervisors["task-0"].Status(); status != workerSupervisorIdle {
+		t.Errorf("expected stopped task to stay %s, got %s", workerSupervisorIdle, status)
+	}
+
+	if err := engine.StartTask("task-0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := runningTasks(engine); got != tasks {
+		t.Errorf("expected restarted task to keep running, got %d running tasks", got)
+	}
+}
+
+func TestShardingDoesNotRestartCompletedTasks(t *testing.T) {
+	engine := newShardedEngine(t, memory.NewStore(), "node-a", 0)
+	completed, unsubscribe := engine.Subscribe(EventFilter{Types: []EventType{EventSupervisorCompleted}})
+	defer unsubscribe()
+
+	trigger, _ := NewAtTrigger(time.Now().Add(10 * time.Millisecond))
+	registerTestTask(t, engine, "task", trigger)
+
+	engine.Start()
+	defer engine.Shutdown()
+
+	select {
+	case <-completed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the task to complete")
+	}
+
+	started, unsubscribeStarted := engine.Subscribe(EventFilter{Types: []EventType{EventSupervisorStarted}})
+	defer unsubscribeStarted()
+
+	select {
+	case <-started:
+		t.Error("expected the completed task not to be started again")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if status := engine.supervisors["task"].Status(); status != workerSupervisorCompleted {
+		t.Errorf("expected task to stay %s, got %s", workerSupervisorCompleted, status)
+	}
+}
@@ -0,0 +1,58 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) log(level slog.Level, msg string) {
+	l.logger.Log(context.Background(), level, msg)
+}
+
+func (l *slogLogger) Info(msg string) { l.log(slog.LevelInfo, msg) }
+
+func (l *slogLogger) Infof(format string, args ...any) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Warn(msg string) { l.log(slog.LevelWarn, msg) }
+
+func (l *slogLogger) Warnf(format string, args ...any) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Error(msg string) { l.log(slog.LevelError, msg) }
+
+func (l *slogLogger) Errorf(format string, args ...any) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) With(args ...any) Logger {
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+// NewSlogLogger adapts logger to the Logger interface.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+// SlogLoggerFactory returns a LoggerFactory whose loggers write to logger,
+// or to slog.Default when it is nil, with the module as a "module"
+// attribute.
+func SlogLoggerFactory(logger *slog.Logger) LoggerFactory {
+	return func(module string) Logger {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		if module != "" {
+			l = l.With("module", module)
+		}
+		return &slogLogger{logger: l}
+	}
+}
@@ -0,0 +1,79 @@
+package taskengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogLoggerFactory(t *testing.T) {
+	var buf bytes.Buffer
+	factory := SlogLoggerFactory(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	task, _ := newTestTask(t, func(ctx *Context) error {
+		ctx.Logger().Info("from job")
+		return nil
+	})
+	task.setLogger(factory)
+
+	tick := &Tick{currentTick: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	task.Execute(context.Background(), tick)
+
+	var record map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "from job") {
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if record == nil {
+		t.Fatalf("expected the job log line, got %q", buf.String())
+	}
+
+	expected := map[string]any{
+		"level":   "INFO",
+		"module":  "task",
+		"task":    "task",
+		"tick":    "2024-01-02T03:04:05Z",
+		"attempt": float64(1),
+	}
+	for key, want := range expected {
+		if got := record[key]; got != want {
+			t.Errorf("expected %s=%v, got %v", key, want, got)
+		}
+	}
+	if id, _ := record["execution_id"].(string); len(id) != 16 {
+		t.Errorf("expected a 16 character execution id, got %q", id)
+	}
+}
+
+func TestSlogLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	tests := []struct {
+		name     string
+		logFunc  func()
+		expected string
+	}{
+		{"Infof", func() { logger.Infof("info %d", 1) }, `level=INFO msg="info 1"`},
+		{"Warn", func() { logger.Warn("warning") }, `level=WARN msg=warning`},
+		{"Errorf", func() { logger.With("task", "a").Errorf("error %s", "x") }, `level=ERROR msg="error x" task=a`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.logFunc()
+
+			if got := buf.String(); !strings.Contains(got, tc.expected) {
+				t.Errorf("expected output to contain %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
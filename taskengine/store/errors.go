@@ -0,0 +1,9 @@
+package store
+
+import "errors"
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskAlreadyExists = errors.New("task already exists")
+	ErrExecutionNotFound = errors.New("execution not found")
+)
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// claimKey identifies a tick independently of its location, so that engines
+// in different zones agree on it.
+func claimKey(tick time.Time) string { return tick.UTC().Format(time.RFC3339Nano) }
+
+func (ms *MemoryStore) ClaimTick(name string, tick time.Time, owner string) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return false, store.ErrTaskNotFound
+	}
+
+	key := claimKey(tick)
+	if claimedBy, claimed := t.Claims[key]; claimed {
+		return claimedBy == owner, nil
+	}
+
+	if t.Claims == nil {
+		t.Claims = make(map[string]string)
+	}
+	t.Claims[key] = owner
+	return true, ms.writeSnapshot()
+}
+
+// pruneClaims deletes the claims on ticks before cutoff and reports whether
+// any was deleted.
+func (t *task) pruneClaims(cutoff time.Time) bool {
+	pruned := false
+	for key := range t.Claims {
+		tick, err := time.Parse(time.RFC3339Nano, key)
+		if err == nil && tick.Before(cutoff) {
+			delete(t.Claims, key)
+			pruned = true
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+func copyExecution(execution *store.Execution) *store.Execution {
+	info := *execution.ExecutionInfo
+	return &store.Execution{
+		ExecutionInfo: &info,
+		TaskID:        execution.TaskID,
+		Iteration:     execution.Iteration,
+	}
+}
+
+func matchesFilter(execution *store.Execution, filter *store.ExecutionFilter) bool {
+	if len(filter.Statuses) > 0 {
+		matched := false
+		for _, status := range filter.Statuses {
+			if execution.Status == status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if !filter.TickFrom.IsZero() && execution.Tick.Before(filter.TickFrom) {
+		return false
+	}
+	if !filter.TickTo.IsZero() && !execution.Tick.Before(filter.TickTo) {
+		return false
+	}
+	if filter.ParentExecutionID != "" && execution.ParentExecutionID != filter.ParentExecutionID {
+		return false
+	}
+	if !filter.StartFrom.IsZero() && execution.StartTime.Before(filter.StartFrom) {
+		return false
+	}
+	if !filter.StartTo.IsZero() && !execution.StartTime.Before(filter.StartTo) {
+		return false
+	}
+	return true
+}
+
+func (ms *MemoryStore) GetExecution(name string, iteration int) (*store.Execution, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return nil, store.ErrTaskNotFound
+	}
+
+	for _, execution := range t.Executions {
+		if execution.Iteration == iteration {
+			return copyExecution(execution), nil
+		}
+	}
+	return nil, store.ErrExecutionNotFound
+}
+
+func (ms *MemoryStore) ListExecutions(
+	name string, filter *store.ExecutionFilter,
+) ([]*store.Execution, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return nil, store.ErrTaskNotFound
+	}
+
+	if filter == nil {
+		filter = &store.ExecutionFilter{}
+	}
+
+	// Executions are kept in iteration order.
+	matched := []*store.Execution{}
+	for i := range t.Executions {
+		execution := t.Executions[len(t.Executions)-1-i]
+		if filter.Order == store.ExecutionOrderOldestFirst {
+			execution = t.Executions[i]
+		}
+		if matchesFilter(execution, filter) {
+			matched = append(matched, execution)
+		}
+	}
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(matched) {
+			return []*store.Execution{}, nil
+		}
+		matched = matched[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	executions := make([]*store.Execution, len(matched))
+	for i, execution := range matched {
+		executions[i] = copyExecution(execution)
+	}
+	return executions, nil
+}
+
+func isFailure(status store.ExecutionStatus) bool {
+	return status == store.ExecutionStatusError || status == store.ExecutionStatusPanic
+}
+
+func (ms *MemoryStore) PruneExecutions(
+	name string, policy *store.RetentionPolicy,
+) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return 0, store.ErrTaskNotFound
+	}
+
+	cutoff := time.Now().Add(-policy.MaxAge)
+	failures := 0
+	scheduledKept := false
+	kept := make([]*store.Execution, 0, len(t.Executions))
+
+	// Walk from the newest execution so positions match the policy.
+	for i := len(t.Executions) - 1; i >= 0; i-- {
+		execution := t.Executions[i]
+		position := len(t.Executions) - i
+
+		// The newest scheduled execution holds the tick to resume from.
+		keep := !execution.Manual && !scheduledKept
+		scheduledKept = scheduledKept || keep
+		if isFailure(execution.Status) {
+			failures++
+			keep = keep || failures <= policy.KeepFailures
+		}
+
+		expired := policy.MaxAge > 0 && execution.StartTime.Before(cutoff)
+		overflow := policy.MaxRows > 0 && position > policy.MaxRows
+		if keep || !(expired || overflow) {
+			kept = append(kept, execution)
+		}
+	}
+
+	claimsPruned := policy.MaxAge > 0 && t.pruneClaims(cutoff)
+
+	pruned := len(t.Executions) - len(kept)
+	if pruned == 0 {
+		if claimsPruned {
+			return 0, ms.writeSnapshot()
+		}
+		return 0, nil
+	}
+
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	t.Executions = kept
+	return pruned, ms.writeSnapshot()
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+var (
+	_ store.Store   = (*MemoryStore)(nil)
+	_ store.Flusher = (*MemoryStore)(nil)
+)
+
+// DefaultSnapshotInterval is how often changes are written to the snapshot
+// unless WithSnapshotInterval says otherwise.
+const DefaultSnapshotInterval = time.Second
+
+type task struct {
+	ID         int                `json:"id"`
+	Settings   store.TaskSettings `json:"settings"`
+	Status     store.TaskStatus   `json:"status"`
+	Iteration  int                `json:"iteration"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Executions []*store.Execution `json:"executions"`
+	Claims     map[string]string  `json:"claims,omitempty"`
+
+	Occurrences int `json:"occurrences,omitempty"`
+}
+
+type state struct {
+	LastID int                  `json:"last_id"`
+	Tasks  map[string]*task     `json:"tasks"`
+	Nodes  map[string]time.Time `json:"nodes,omitempty"`
+}
+
+func newState() state {
+	return state{
+		Tasks: make(map[string]*task),
+		Nodes: make(map[string]time.Time),
+	}
+}
+
+// MemoryStore keeps every task and execution in process memory. When a
+// snapshot file is configured, the whole state is written to it once per
+// snapshot interval after a change, on Flush, and loaded back by
+// CreateStores.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	state state
+
+	snapshotPath     string
+	snapshotInterval time.Duration
+	dirty            bool
+	flushTimer       *time.Timer
+	flushErr         error
+}
+
+func (ms *MemoryStore) CreateStores() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.snapshotPath == "" || len(ms.state.Tasks) > 0 {
+		return nil
+	}
+	return ms.loadSnapshot()
+}
+
+func (ms *MemoryStore) DeleteStores() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.state = newState()
+	return ms.removeSnapshot()
+}
+
+func (ms *MemoryStore) ClearStores() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.state = newState()
+	return ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) TaskExists(name string) (bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	_, exists := ms.state.Tasks[name]
+	return exists, nil
+}
+
+func (ms *MemoryStore) SaveTask(name string, settings *store.TaskSettings) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.state.Tasks[name]; exists {
+		return store.ErrTaskAlreadyExists
+	}
+
+	ms.state.LastID++
+	ms.state.Tasks[name] = &task{
+		ID:        ms.state.LastID,
+		Settings:  *settings,
+		Status:    store.TaskStatusIdle,
+		CreatedAt: time.Now(),
+	}
+	return ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) GetTaskSettings(name string) (*store.TaskSettings, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return nil, store.ErrTaskNotFound
+	}
+
+	settings := t.Settings
+	return &settings, nil
+}
+
+func (ms *MemoryStore) UpdateTaskStatus(name string, status store.TaskStatus) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return store.ErrTaskNotFound
+	}
+
+	t.Status = status
+	return ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) GetTaskStatus(name string) (store.TaskStatus, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return "", store.ErrTaskNotFound
+	}
+	return t.Status, nil
+}
+
+func (ms *MemoryStore) GetOccurrences(name string) (int, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return 0, store.ErrTaskNotFound
+	}
+	return t.Occurrences, nil
+}
+
+func (ms *MemoryStore) IncrementOccurrences(name string) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return 0, store.ErrTaskNotFound
+	}
+
+	t.Occurrences++
+	return t.Occurrences, ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) SaveExecution(name string, info *store.ExecutionInfo) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return store.ErrTaskNotFound
+	}
+
+	t.Iteration++
+	infoCopy := *info
+	t.Executions = append(t.Executions, &store.Execution{
+		ExecutionInfo: &infoCopy,
+		TaskID:        t.ID,
+		Iteration:     t.Iteration,
+	})
+	return ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) GetLastTick(name string) (time.Time, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	t, exists := ms.state.Tasks[name]
+	if !exists {
+		return time.Time{}, store.ErrTaskNotFound
+	}
+
+	for i := len(t.Executions) - 1; i >= 0; i-- {
+		if !t.Executions[i].Manual {
+			return t.Executions[i].Tick, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+func NewStore(options ...Option) *MemoryStore {
+	ms := &MemoryStore{state: newState(), snapshotInterval: DefaultSnapshotInterval}
+
+	for _, opt := range options {
+		opt(ms)
+	}
+
+	return ms
+}
+
+type Option func(*MemoryStore)
+
+// WithSnapshot persists the store state as JSON at path so that a new
+// process can recover tasks, iterations and last ticks.
+func WithSnapshot(path string) Option {
+	return func(ms *MemoryStore) {
+		ms.snapshotPath = path
+	}
+}
+
+// WithSnapshotInterval sets how often changes are written to the snapshot.
+// A non-positive interval writes the snapshot after every change.
+func WithSnapshotInterval(interval time.Duration) Option {
+	return func(ms *MemoryStore) {
+		ms.snapshotInterval = interval
+	}
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.RunConformance(t, func(t *testing.T) store.Store {
+		return NewStore()
+	})
+}
+
+func TestSaveExecutionIncreasesIteration(t *testing.T) {
+	ms := NewStore()
+	if err := ms.SaveTask("task", &store.TaskSettings{Job: "job"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		err := ms.SaveExecution("task", &store.ExecutionInfo{
+			Status: store.ExecutionStatusSuccess,
+			Tick:   time.Unix(int64(i), 0),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	executions := ms.state.Tasks["task"].Executions
+	if len(executions) != 3 {
+		t.Fatalf("expected 3 executions, got %d", len(executions))
+	}
+	for i, e := range executions {
+		if e.Iteration != i+1 {
+			t.Errorf("expected iteration %d, got %d", i+1, e.Iteration)
+		}
+	}
+
+	lastTick, err := ms.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Unix(3, 0); !lastTick.Equal(want) {
+		t.Errorf("expected last tick %v, got %v", want, lastTick)
+	}
+}
+
+func TestUnknownTask(t *testing.T) {
+	ms := NewStore()
+
+	if err := ms.UpdateTaskStatus("missing", store.TaskStatusRunning); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	if err := ms.SaveExecution("missing", &store.ExecutionInfo{}); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	if _, err := ms.GetLastTick("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	tick := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ms := NewStore(WithSnapshot(path))
+	if err := ms.CreateStores(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ms.SaveTask("task", &store.TaskSettings{Job: "job", Policy: "serial"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ms.SaveExecution("task", &store.ExecutionInfo{Tick: tick}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ms.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored := NewStore(WithSnapshot(path))
+	if err := restored.CreateStores(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	settings, err := restored.GetTaskSettings("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Policy != "serial" {
+		t.Errorf("expected policy serial, got %s", settings.Policy)
+	}
+
+	lastTick, err := restored.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !lastTick.Equal(tick) {
+		t.Errorf("expected last tick %v, got %v", tick, lastTick)
+	}
+
+	if err := restored.DeleteStores(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewStore(WithSnapshot(path)).CreateStores(); err != nil {
+		t.Errorf("expected missing snapshot to be ignored, got %v", err)
+	}
+}
+
+func TestSnapshotDebounce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		written  bool
+	}{
+		{"debounced", time.Hour, false},
+		{"immediate", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := NewStore(WithSnapshot(path), WithSnapshotInterval(tc.interval))
+			defer ms.DeleteStores()
+
+			if err := ms.SaveTask("task", &store.TaskSettings{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			exists := func() bool {
+				restored := NewStore(WithSnapshot(path))
+				if err := restored.CreateStores(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				ok, _ := restored.TaskExists("task")
+				return ok
+			}
+
+			if got := exists(); got != tc.written {
+				t.Errorf("expected task written before flush %v, got %v", tc.written, got)
+			}
+			if err := ms.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !exists() {
+				t.Error("expected task written after flush")
+			}
+		})
+	}
+}
+
+func TestSnapshotWrittenAfterInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	ms := NewStore(WithSnapshot(path), WithSnapshotInterval(10*time.Millisecond))
+
+	if err := ms.SaveTask("task", &store.TaskSettings{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		restored := NewStore(WithSnapshot(path))
+		if err := restored.CreateStores(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok, _ := restored.TaskExists("task"); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the snapshot to be written after the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
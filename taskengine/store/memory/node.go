@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"sort"
+	"time"
+)
+
+func (ms *MemoryStore) Heartbeat(nodeID string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.state.Nodes[nodeID] = time.Now()
+	return ms.writeSnapshot()
+}
+
+func (ms *MemoryStore) ListNodes(since time.Time) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	nodes := []string{}
+	for id, lastSeen := range ms.state.Nodes {
+		if !lastSeen.Before(since) {
+			nodes = append(nodes, id)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+func (ms *MemoryStore) RemoveNode(nodeID string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.state.Nodes[nodeID]; !exists {
+		return nil
+	}
+	delete(ms.state.Nodes, nodeID)
+	return ms.writeSnapshot()
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func (ms *MemoryStore) loadSnapshot() error {
+	data, err := os.ReadFile(ms.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s := newState()
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]*task)
+	}
+	if s.Nodes == nil {
+		s.Nodes = make(map[string]time.Time)
+	}
+
+	ms.state = s
+	return nil
+}
+
+// writeSnapshot records a change of the state. Changes are written to the
+// snapshot at most once per snapshot interval, so that a large history is not
+// rewritten on every execution; without an interval they are written at once.
+func (ms *MemoryStore) writeSnapshot() error {
+	if ms.snapshotPath == "" {
+		return nil
+	}
+	if ms.snapshotInterval <= 0 {
+		return ms.flushSnapshot()
+	}
+
+	ms.dirty = true
+	if ms.flushTimer == nil {
+		ms.flushTimer = time.AfterFunc(ms.snapshotInterval, ms.flushPending)
+	}
+
+	// A failed background write is reported by the next change.
+	err := ms.flushErr
+	ms.flushErr = nil
+	return err
+}
+
+func (ms *MemoryStore) flushPending() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.flushTimer = nil
+	if ms.dirty {
+		ms.flushErr = ms.flushSnapshot()
+	}
+}
+
+// Flush writes pending changes to the snapshot right away.
+func (ms *MemoryStore) Flush() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.stopFlushTimer()
+	err := ms.flushErr
+	ms.flushErr = nil
+	if ms.dirty {
+		err = ms.flushSnapshot()
+	}
+	return err
+}
+
+func (ms *MemoryStore) stopFlushTimer() {
+	if ms.flushTimer != nil {
+		ms.flushTimer.Stop()
+		ms.flushTimer = nil
+	}
+}
+
+// flushSnapshot replaces the snapshot file atomically so a crash while
+// writing never leaves a truncated snapshot behind.
+func (ms *MemoryStore) flushSnapshot() error {
+	data, err := json.Marshal(&ms.state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ms.snapshotPath), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), ms.snapshotPath); err != nil {
+		return err
+	}
+	ms.dirty = false
+	return nil
+}
+
+func (ms *MemoryStore) removeSnapshot() error {
+	if ms.snapshotPath == "" {
+		return nil
+	}
+
+	ms.stopFlushTimer()
+	ms.dirty, ms.flushErr = false, nil
+
+	err := os.Remove(ms.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
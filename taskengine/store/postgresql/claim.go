@@ -0,0 +1,86 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// Ticks are claimed in UTC: TIMESTAMP drops the zone, and every engine must
+// agree on the value of a tick.
+type claimStore struct {
+	db DB
+}
+
+func (cs *claimStore) createStore() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS tick_claims (
+			task_id     INT        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			tick        TIMESTAMP  NOT NULL,
+			owner       TEXT       NOT NULL,
+			claimed_at  TIMESTAMP  NOT NULL,
+			PRIMARY KEY (task_id, tick)
+		);
+	`
+	_, err := cs.db.Exec(query)
+	return err
+}
+
+func (cs *claimStore) deleteStore() error {
+	query := "DROP TABLE IF EXISTS tick_claims;"
+	_, err := cs.db.Exec(query)
+	return err
+}
+
+func (cs *claimStore) clearStore() error {
+	query := "DELETE FROM tick_claims;"
+	_, err := cs.db.Exec(query)
+	return err
+}
+
+func (cs *claimStore) claim(taskName string, tick time.Time, owner string) (bool, error) {
+	query := `
+		INSERT INTO tick_claims (task_id, tick, owner, claimed_at)
+		SELECT id, $1, $2, $3 FROM tasks WHERE name = $4
+		ON CONFLICT (task_id, tick) DO NOTHING;
+	`
+	result, err := cs.db.Exec(query, tick.UTC(), owner, time.Now().UTC(), taskName)
+	if err != nil {
+		return false, err
+	}
+	if err := expectAffected(result, store.ErrTaskNotFound); err == nil {
+		return true, nil
+	}
+
+	// Nothing was inserted: either the tick is claimed or the task is unknown.
+	query = `
+		SELECT c.owner
+		FROM tick_claims c
+		JOIN tasks t ON c.task_id = t.id
+		WHERE t.name = $1 AND c.tick = $2;
+	`
+	var claimedBy string
+	err = cs.db.QueryRow(query, taskName, tick.UTC()).Scan(&claimedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, store.ErrTaskNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return claimedBy == owner, nil
+}
+
+func (cs *claimStore) prune(taskName string, cutoff time.Time) error {
+	query := `
+		DELETE FROM tick_claims
+		WHERE task_id = (SELECT id FROM tasks WHERE name = $1) AND tick < $2;
+	`
+	_, err := cs.db.Exec(query, taskName, cutoff.UTC())
+	return err
+}
+
+func newClaimStore(db DB) *claimStore {
+	return &claimStore{db: db}
+}
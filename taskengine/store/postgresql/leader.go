@@ -0,0 +1,98 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+type connDB struct {
+	conn *sql.Conn
+}
+
+func (c *connDB) Exec(query string, args ...any) (sql.Result, error) {
+	return c.conn.ExecContext(context.Background(), query, args...)
+}
+
+func (c *connDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.conn.QueryContext(context.Background(), query, args...)
+}
+
+func (c *connDB) QueryRow(query string, args ...any) *sql.Row {
+	return c.conn.QueryRowContext(context.Background(), query, args...)
+}
+
+// NewConnDB adapts conn to DB so that every statement runs in the same
+// database session.
+func NewConnDB(conn *sql.Conn) DB { return &connDB{conn: conn} }
+
+// LeaderElector grants leadership to whichever engine holds a session-level
+// advisory lock on key. Such locks belong to the session that took them, so
+// db must run every statement on one connection, e.g. a DB from NewConnDB.
+// Leadership is lost when that session ends.
+type LeaderElector struct {
+	mu sync.Mutex
+
+	db   DB
+	key  int64
+	held bool
+}
+
+func (le *LeaderElector) TryAcquire() (bool, error) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	if le.held {
+		held, err := le.holdsLock()
+		if err != nil || held {
+			le.held = held
+			return held, err
+		}
+	}
+
+	query := "SELECT pg_try_advisory_lock($1);"
+	err := le.db.QueryRow(query, le.key).Scan(&le.held)
+	if err != nil {
+		le.held = false
+	}
+	return le.held, err
+}
+
+// holdsLock reports whether the current session still holds the lock. A
+// bigint advisory key is split across classid and objid in pg_locks.
+func (le *LeaderElector) holdsLock() (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory'
+			  AND pid = pg_backend_pid()
+			  AND granted
+			  AND classid::bigint = $1
+			  AND objid::bigint = $2
+			  AND objsubid = 1
+		);
+	`
+	var held bool
+	err := le.db.QueryRow(
+		query, int64(uint32(le.key>>32)), int64(uint32(le.key)),
+	).Scan(&held)
+	return held, err
+}
+
+func (le *LeaderElector) Release() error {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	if !le.held {
+		return nil
+	}
+
+	query := "SELECT pg_advisory_unlock($1);"
+	_, err := le.db.Exec(query, le.key)
+	le.held = false
+	return err
+}
+
+func NewLeaderElector(db DB, key int64) *LeaderElector {
+	return &LeaderElector{db: db, key: key}
+}
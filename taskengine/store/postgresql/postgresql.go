@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/MAD-py/go-taskengine/taskengine/store"
@@ -9,14 +10,48 @@ import (
 
 type DB interface {
 	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
 	QueryRow(query string, args ...any) *sql.Row
 }
 
+// expectAffected returns errNone when result reports that no row was
+// touched, which is how missing or conflicting rows surface in this store.
+func expectAffected(result sql.Result, errNone error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errNone
+	}
+	return nil
+}
+
+// column is a column added to a table after its first release.
+type column struct {
+	name       string
+	definition string
+}
+
+// addColumns adds the columns that table is missing, so databases created
+// by older versions pick up the current schema.
+func addColumns(db DB, table string, columns []column) error {
+	for _, c := range columns {
+		query := "ALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS " + c.name + " " + c.definition + ";"
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var _ store.Store = (*PostgresStore)(nil)
 
 type PostgresStore struct {
 	taskStore      *taskStore
 	executionStore *executionStore
+	claimStore     *claimStore
+	nodeStore      *nodeStore
 }
 
 func (ps *PostgresStore) CreateStores() error {
@@ -26,10 +61,22 @@ func (ps *PostgresStore) CreateStores() error {
 	if err := ps.executionStore.createStore(); err != nil {
 		return err
 	}
+	if err := ps.claimStore.createStore(); err != nil {
+		return err
+	}
+	if err := ps.nodeStore.createStore(); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (ps *PostgresStore) DeleteStores() error {
+	if err := ps.nodeStore.deleteStore(); err != nil {
+		return err
+	}
+	if err := ps.claimStore.deleteStore(); err != nil {
+		return err
+	}
 	if err := ps.executionStore.deleteStore(); err != nil {
 		return err
 	}
@@ -40,6 +87,12 @@ func (ps *PostgresStore) DeleteStores() error {
 }
 
 func (ps *PostgresStore) ClearStores() error {
+	if err := ps.nodeStore.clearStore(); err != nil {
+		return err
+	}
+	if err := ps.claimStore.clearStore(); err != nil {
+		return err
+	}
 	if err := ps.executionStore.clearStore(); err != nil {
 		return err
 	}
@@ -65,6 +118,10 @@ func (ps *PostgresStore) UpdateTaskStatus(name string, status store.TaskStatus)
 	return ps.taskStore.updateStatus(name, status)
 }
 
+func (ps *PostgresStore) GetTaskStatus(name string) (store.TaskStatus, error) {
+	return ps.taskStore.getStatus(name)
+}
+
 func (ps *PostgresStore) SaveExecution(name string, info *store.ExecutionInfo) error {
 	taskID, iteration, err := ps.taskStore.increaseIteration(name)
 	if err != nil {
@@ -84,9 +141,79 @@ func (ps *PostgresStore) GetLastTick(name string) (time.Time, error) {
 	return ps.executionStore.getLastTick(name)
 }
 
+func (ps *PostgresStore) GetExecution(name string, iteration int) (*store.Execution, error) {
+	execution, err := ps.executionStore.get(name, iteration)
+	if errors.Is(err, store.ErrExecutionNotFound) {
+		return nil, ps.taskStore.notFound(name, err)
+	}
+	return execution, err
+}
+
+func (ps *PostgresStore) ListExecutions(
+	name string, filter *store.ExecutionFilter,
+) ([]*store.Execution, error) {
+	executions, err := ps.executionStore.list(name, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(executions) == 0 {
+		if err := ps.taskStore.notFound(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return executions, nil
+}
+
+func (ps *PostgresStore) PruneExecutions(
+	name string, policy *store.RetentionPolicy,
+) (int, error) {
+	now := time.Now()
+	pruned, err := ps.executionStore.prune(name, policy, now)
+	if err != nil {
+		return pruned, err
+	}
+	if policy.MaxAge > 0 {
+		if err := ps.claimStore.prune(name, now.Add(-policy.MaxAge)); err != nil {
+			return pruned, err
+		}
+	}
+	if pruned == 0 {
+		if err := ps.taskStore.notFound(name, nil); err != nil {
+			return 0, err
+		}
+	}
+	return pruned, nil
+}
+
+func (ps *PostgresStore) ClaimTick(name string, tick time.Time, owner string) (bool, error) {
+	return ps.claimStore.claim(name, tick, owner)
+}
+
+func (ps *PostgresStore) GetOccurrences(name string) (int, error) {
+	return ps.taskStore.getOccurrences(name)
+}
+
+func (ps *PostgresStore) IncrementOccurrences(name string) (int, error) {
+	return ps.taskStore.increaseOccurrences(name)
+}
+
+func (ps *PostgresStore) Heartbeat(nodeID string) error {
+	return ps.nodeStore.heartbeat(nodeID)
+}
+
+func (ps *PostgresStore) ListNodes(since time.Time) ([]string, error) {
+	return ps.nodeStore.list(since)
+}
+
+func (ps *PostgresStore) RemoveNode(nodeID string) error {
+	return ps.nodeStore.remove(nodeID)
+}
+
 func NewStore(db DB) *PostgresStore {
 	return &PostgresStore{
 		taskStore:      newTaskStore(db),
 		executionStore: newExecutionStore(db),
+		claimStore:     newClaimStore(db),
+		nodeStore:      newNodeStore(db),
 	}
 }
@@ -0,0 +1,153 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/storetest"
+
+	_ "github.com/lib/pq"
+)
+
+// The suite needs a disposable database; its tables are dropped and
+// recreated for every subtest.
+const dsnEnv = "TASKENGINE_POSTGRES_DSN"
+
+// baselineSchema is the schema of the first release of this store, before
+// any column was added to its tables.
+const baselineSchema = `
+	CREATE TABLE tasks (
+		id          SERIAL     PRIMARY KEY,
+		name        TEXT       NOT NULL UNIQUE,
+		job         TEXT       NOT NULL,
+		trigger     TEXT       NOT NULL,
+		policy      TEXT       NOT NULL,
+		status      TEXT       NOT NULL DEFAULT 'idle',
+		iteration   INT        NOT NULL DEFAULT 0,
+		created_at  TIMESTAMP  NOT NULL DEFAULT NOW()
+	);
+	CREATE TABLE executions (
+		id          SERIAL     PRIMARY KEY,
+		task_id     INT        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		iteration   INT        NOT NULL,
+		start_time  TIMESTAMP  NOT NULL,
+		end_time    TIMESTAMP  NOT NULL,
+		duration    BIGINT     NOT NULL,
+		status      TEXT       NOT NULL,
+		tick        TIMESTAMP  NOT NULL,
+		error_msg   TEXT
+	);
+`
+
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	storetest.RunConformance(t, func(t *testing.T) store.Store {
+		ps := NewStore(db)
+		if err := ps.DeleteStores(); err != nil {
+			t.Fatalf("failed to delete stores: %v", err)
+		}
+		if err := ps.CreateStores(); err != nil {
+			t.Fatalf("failed to create stores: %v", err)
+		}
+		t.Cleanup(func() { ps.DeleteStores() })
+		return ps
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	storetest.RunUpgrade(t, func(t *testing.T) store.Store {
+		ps := NewStore(db)
+		if err := ps.DeleteStores(); err != nil {
+			t.Fatalf("failed to delete stores: %v", err)
+		}
+		if _, err := db.Exec(baselineSchema); err != nil {
+			t.Fatalf("failed to create baseline schema: %v", err)
+		}
+		if err := ps.CreateStores(); err != nil {
+			t.Fatalf("failed to create stores: %v", err)
+		}
+		t.Cleanup(func() { ps.DeleteStores() })
+		return ps
+	})
+}
+
+func TestLeaderElector(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	newElector := func() *LeaderElector {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("failed to open connection: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return NewLeaderElector(NewConnDB(conn), 42)
+	}
+
+	first, second := newElector(), newElector()
+
+	tests := []struct {
+		name     string
+		elector  *LeaderElector
+		release  bool
+		expected bool
+	}{
+		{"first acquires", first, false, true},
+		{"second is a follower", second, false, false},
+		{"first renews", first, false, true},
+		{"second takes over", second, true, true},
+		{"first is a follower", first, false, false},
+	}
+
+	for _, tc := range tests {
+		if tc.release {
+			if err := first.Release(); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+		}
+
+		leader, err := tc.elector.TryAcquire()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if leader != tc.expected {
+			t.Errorf("%s: expected leader %v, got %v", tc.name, tc.expected, leader)
+		}
+	}
+
+	if err := second.Release(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package postgresql
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/MAD-py/go-taskengine/taskengine/store"
 )
 
@@ -8,6 +11,12 @@ type taskStore struct {
 	db DB
 }
 
+// addedTaskColumns lists the tasks columns that tables created by older
+// versions may lack.
+var addedTaskColumns = []column{
+	{"occurrences", "INT NOT NULL DEFAULT 0"},
+}
+
 func (ts *taskStore) createStore() error {
 	query := `
 		CREATE TABLE IF NOT EXISTS tasks (
@@ -18,11 +27,14 @@ func (ts *taskStore) createStore() error {
 			policy      TEXT       NOT NULL,
 			status		TEXT       NOT NULL DEFAULT 'idle',
 			iteration   INT        NOT NULL DEFAULT 0,
+			occurrences INT        NOT NULL DEFAULT 0,
 			created_at  TIMESTAMP  NOT NULL DEFAULT NOW()
 		);
 	`
-	_, err := ts.db.Exec(query)
-	return err
+	if _, err := ts.db.Exec(query); err != nil {
+		return err
+	}
+	return addColumns(ts.db, "tasks", addedTaskColumns)
 }
 
 func (ts *taskStore) deleteStore() error {
@@ -32,7 +44,7 @@ func (ts *taskStore) deleteStore() error {
 }
 
 func (ts *taskStore) clearStore() error {
-	query := "TRUNCATE TABLE tasks RESTART IDENTITY;"
+	query := "TRUNCATE TABLE tasks RESTART IDENTITY CASCADE;"
 	_, err := ts.db.Exec(query)
 	return err
 }
@@ -50,14 +62,18 @@ func (ts *taskStore) save(
 	query := `
 		INSERT INTO tasks (name, job, trigger, policy)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id;
+		ON CONFLICT (name) DO NOTHING;
 	`
-	return ts.db.QueryRow(
+	result, err := ts.db.Exec(
 		query, name,
 		settings.Job,
 		settings.Trigger,
 		settings.Policy,
-	).Err()
+	)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, store.ErrTaskAlreadyExists)
 }
 
 func (ts *taskStore) getSettings(name string) (*store.TaskSettings, error) {
@@ -71,6 +87,9 @@ func (ts *taskStore) getSettings(name string) (*store.TaskSettings, error) {
 	err := ts.db.
 		QueryRow(query, name).
 		Scan(&settings.Job, &settings.Trigger, &settings.Policy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrTaskNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +100,22 @@ func (ts *taskStore) updateStatus(
 	name string, status store.TaskStatus,
 ) error {
 	query := "UPDATE tasks SET status = $2 WHERE name = $1;"
-	_, err := ts.db.Exec(query, name, status)
-	return err
+	result, err := ts.db.Exec(query, name, status)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, store.ErrTaskNotFound)
+}
+
+func (ts *taskStore) getStatus(name string) (store.TaskStatus, error) {
+	query := "SELECT status FROM tasks WHERE name = $1;"
+
+	var status store.TaskStatus
+	err := ts.db.QueryRow(query, name).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", store.ErrTaskNotFound
+	}
+	return status, err
 }
 
 func (ts *taskStore) increaseIteration(name string) (int, int, error) {
@@ -95,12 +128,54 @@ func (ts *taskStore) increaseIteration(name string) (int, int, error) {
 	var id int
 	var iteration int
 	err := ts.db.QueryRow(query, name).Scan(&id, &iteration)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, store.ErrTaskNotFound
+	}
 	if err != nil {
 		return 0, 0, err
 	}
 	return id, iteration, nil
 }
 
+func (ts *taskStore) getOccurrences(name string) (int, error) {
+	query := "SELECT occurrences FROM tasks WHERE name = $1;"
+
+	var occurrences int
+	err := ts.db.QueryRow(query, name).Scan(&occurrences)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, store.ErrTaskNotFound
+	}
+	return occurrences, err
+}
+
+func (ts *taskStore) increaseOccurrences(name string) (int, error) {
+	query := `
+		UPDATE tasks
+		SET occurrences = occurrences + 1
+		WHERE name = $1
+		RETURNING occurrences;
+	`
+	var occurrences int
+	err := ts.db.QueryRow(query, name).Scan(&occurrences)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, store.ErrTaskNotFound
+	}
+	return occurrences, err
+}
+
+// notFound returns ErrTaskNotFound if the task does not exist and err
+// otherwise, so that empty results can tell both situations apart.
+func (ts *taskStore) notFound(name string, err error) error {
+	exists, existsErr := ts.exists(name)
+	if existsErr != nil {
+		return existsErr
+	}
+	if !exists {
+		return store.ErrTaskNotFound
+	}
+	return err
+}
+
 func newTaskStore(db DB) *taskStore {
 	return &taskStore{db: db}
 }
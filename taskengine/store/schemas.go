@@ -18,6 +18,30 @@ const (
 	ExecutionStatusSkipped ExecutionStatus = "skipped"
 )
 
+// SkipReason explains why a tick was recorded as ExecutionStatusSkipped
+// instead of running the job.
+type SkipReason string
+
+const (
+	// SkipReasonBusy: the previous execution was still running under
+	// the skip-if-busy worker policy.
+	SkipReasonBusy SkipReason = "busy"
+	// SkipReasonMaxConcurrency: the task already ran as many executions as
+	// its concurrency limit allows.
+	SkipReasonMaxConcurrency SkipReason = "max_concurrency"
+	// SkipReasonStale: the tick was already in the past and catch-up is
+	// disabled.
+	SkipReasonStale SkipReason = "stale"
+	// SkipReasonPaused: the tick fell while the scheduler was paused and
+	// catch-up is disabled.
+	SkipReasonPaused SkipReason = "paused"
+	// SkipReasonQueueFull: the dispatcher queue had no room for the tick.
+	SkipReasonQueueFull SkipReason = "queue_full"
+	// SkipReasonUpstreamFailed: a workflow task it depends on did not
+	// succeed for the same tick.
+	SkipReasonUpstreamFailed SkipReason = "upstream_failed"
+)
+
 type TaskSettings struct {
 	Job     string `json:"job"`
 	Policy  string `json:"policy"`
@@ -30,7 +54,24 @@ type ExecutionInfo struct {
 	Duration  time.Duration   `json:"duration"`
 	Status    ExecutionStatus `json:"status"`
 	Tick      time.Time       `json:"tick"`
+	Attempt   int             `json:"attempt"`
+	Manual    bool            `json:"manual"`
 	ErrorMsg  string          `json:"error_msg,omitempty"`
+
+	SkipReason SkipReason `json:"skip_reason,omitempty"`
+	// MissedTicks is the number of consecutive stale or paused ticks a
+	// skipped execution stands for; Tick is the latest of them.
+	MissedTicks int `json:"missed_ticks,omitempty"`
+
+	// Logs holds what the job logged through its Context, when the task
+	// captures logs.
+	Logs string `json:"logs,omitempty"`
+
+	// ExecutionID is shared by the attempts of one execution, and
+	// ParentExecutionID links the executions of workflow tasks to the
+	// execution of their workflow.
+	ExecutionID       string `json:"execution_id,omitempty"`
+	ParentExecutionID string `json:"parent_execution_id,omitempty"`
 }
 
 type Execution struct {
@@ -39,3 +80,41 @@ type Execution struct {
 	TaskID    int `json:"task_id"`
 	Iteration int `json:"iteration"`
 }
+
+type ExecutionOrder string
+
+const (
+	ExecutionOrderNewestFirst ExecutionOrder = "newest_first"
+	ExecutionOrderOldestFirst ExecutionOrder = "oldest_first"
+)
+
+// ExecutionFilter narrows down ListExecutions. Zero values disable the
+// corresponding condition; time ranges include From and exclude To.
+// Executions are ordered by iteration, newest first unless Order says
+// otherwise.
+type ExecutionFilter struct {
+	Statuses []ExecutionStatus `json:"statuses,omitempty"`
+
+	TickFrom  time.Time `json:"tick_from,omitempty"`
+	TickTo    time.Time `json:"tick_to,omitempty"`
+	StartFrom time.Time `json:"start_from,omitempty"`
+	StartTo   time.Time `json:"start_to,omitempty"`
+
+	ParentExecutionID string `json:"parent_execution_id,omitempty"`
+
+	Order  ExecutionOrder `json:"order,omitempty"`
+	Limit  int            `json:"limit,omitempty"`
+	Offset int            `json:"offset,omitempty"`
+}
+
+// RetentionPolicy selects the executions removed by PruneExecutions. An
+// execution is pruned when it started more than MaxAge ago or is not among
+// the MaxRows most recent ones, unless it is one of the KeepFailures most
+// recent errors or panics. Zero values disable the corresponding rule, and
+// the most recent scheduled execution is always kept so its tick can be
+// recovered; manual executions are not protected.
+type RetentionPolicy struct {
+	MaxAge       time.Duration `json:"max_age,omitempty"`
+	MaxRows      int           `json:"max_rows,omitempty"`
+	KeepFailures int           `json:"keep_failures,omitempty"`
+}
@@ -0,0 +1,335 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+type executionStore struct {
+	db DB
+}
+
+// addedExecutionColumns lists the executions columns that tables created by
+// older versions may lack.
+var addedExecutionColumns = []column{
+	{"manual", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"attempt", "INTEGER NOT NULL DEFAULT 1"},
+	{"skip_reason", "TEXT"},
+	{"logs", "TEXT"},
+	{"execution_id", "TEXT"},
+	{"parent_execution_id", "TEXT"},
+	{"missed_ticks", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+func (es *executionStore) createStore() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS executions (
+			id                  INTEGER    PRIMARY KEY AUTOINCREMENT,
+			task_id             INTEGER    NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			iteration           INTEGER    NOT NULL,
+			start_time          TIMESTAMP  NOT NULL,
+			end_time            TIMESTAMP  NOT NULL,
+			duration            INTEGER    NOT NULL,
+			status              TEXT       NOT NULL,
+			tick                TIMESTAMP  NOT NULL,
+			manual              BOOLEAN    NOT NULL DEFAULT FALSE,
+			attempt             INTEGER    NOT NULL DEFAULT 1,
+			error_msg           TEXT,
+			skip_reason         TEXT,
+			logs                TEXT,
+			execution_id        TEXT,
+			parent_execution_id TEXT,
+			missed_ticks        INTEGER    NOT NULL DEFAULT 0
+		);
+	`
+
+	if _, err := es.db.Exec(query); err != nil {
+		return err
+	}
+	return addColumns(es.db, "executions", addedExecutionColumns)
+}
+
+func (es *executionStore) deleteStore() error {
+	query := "DROP TABLE IF EXISTS executions;"
+	_, err := es.db.Exec(query)
+	return err
+}
+
+func (es *executionStore) clearStore() error {
+	if _, err := es.db.Exec("DELETE FROM executions;"); err != nil {
+		return err
+	}
+	query := "DELETE FROM sqlite_sequence WHERE name = 'executions';"
+	_, err := es.db.Exec(query)
+	return err
+}
+
+func (es *executionStore) save(execution *store.Execution) error {
+	query := `
+		INSERT INTO executions (task_id, iteration, start_time, end_time, duration, status, tick, attempt, manual, error_msg, skip_reason, logs, execution_id, parent_execution_id, missed_ticks)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	_, err := es.db.Exec(
+		query,
+		execution.TaskID,
+		execution.Iteration,
+		utc(execution.StartTime),
+		utc(execution.EndTime),
+		execution.Duration.Milliseconds(),
+		execution.Status,
+		utc(execution.Tick),
+		execution.Attempt,
+		execution.Manual,
+		nullString(execution.ErrorMsg),
+		nullString(string(execution.SkipReason)),
+		nullString(execution.Logs),
+		nullString(execution.ExecutionID),
+		nullString(execution.ParentExecutionID),
+		execution.MissedTicks,
+	)
+	return err
+}
+
+func (es *executionStore) getLastTick(taskName string) (time.Time, error) {
+	query := `
+		SELECT e.tick
+		FROM tasks t
+		LEFT JOIN executions e ON e.task_id = t.id AND NOT e.manual
+		WHERE t.name = ?
+		ORDER BY e.iteration DESC
+		LIMIT 1;
+	`
+
+	var tick sql.NullTime
+	err := es.db.QueryRow(query, taskName).Scan(&tick)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, store.ErrTaskNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tick.Time, nil
+}
+
+const executionColumns = `
+	e.task_id, e.iteration, e.start_time, e.end_time,
+	e.duration, e.status, e.tick, e.attempt, e.manual, e.error_msg, e.skip_reason, e.logs,
+	e.execution_id, e.parent_execution_id, e.missed_ticks
+`
+
+// nullString stores empty strings as NULL.
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExecution(row rowScanner) (*store.Execution, error) {
+	var duration int64
+	var errorMsg sql.NullString
+	var skipReason sql.NullString
+	var logs sql.NullString
+	var executionID sql.NullString
+	var parentExecutionID sql.NullString
+	execution := &store.Execution{ExecutionInfo: &store.ExecutionInfo{}}
+
+	err := row.Scan(
+		&execution.TaskID,
+		&execution.Iteration,
+		&execution.StartTime,
+		&execution.EndTime,
+		&duration,
+		&execution.Status,
+		&execution.Tick,
+		&execution.Attempt,
+		&execution.Manual,
+		&errorMsg,
+		&skipReason,
+		&logs,
+		&executionID,
+		&parentExecutionID,
+		&execution.MissedTicks,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	execution.Duration = time.Duration(duration) * time.Millisecond
+	execution.ErrorMsg = errorMsg.String
+	execution.SkipReason = store.SkipReason(skipReason.String)
+	execution.Logs = logs.String
+	execution.ExecutionID = executionID.String
+	execution.ParentExecutionID = parentExecutionID.String
+	return execution, nil
+}
+
+func (es *executionStore) get(taskName string, iteration int) (*store.Execution, error) {
+	query := `
+		SELECT ` + executionColumns + `
+		FROM executions e
+		JOIN tasks t ON e.task_id = t.id
+		WHERE t.name = ? AND e.iteration = ?;
+	`
+
+	execution, err := scanExecution(es.db.QueryRow(query, taskName, iteration))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrExecutionNotFound
+	}
+	return execution, err
+}
+
+func (es *executionStore) list(
+	taskName string, filter *store.ExecutionFilter,
+) ([]*store.Execution, error) {
+	if filter == nil {
+		filter = &store.ExecutionFilter{}
+	}
+
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return "?"
+	}
+
+	conditions := []string{"t.name = " + arg(taskName)}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = arg(status)
+		}
+		conditions = append(conditions, "e.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if !filter.TickFrom.IsZero() {
+		conditions = append(conditions, "e.tick >= "+arg(utc(filter.TickFrom)))
+	}
+	if !filter.TickTo.IsZero() {
+		conditions = append(conditions, "e.tick < "+arg(utc(filter.TickTo)))
+	}
+	if filter.ParentExecutionID != "" {
+		conditions = append(conditions, "e.parent_execution_id = "+arg(filter.ParentExecutionID))
+	}
+	if !filter.StartFrom.IsZero() {
+		conditions = append(conditions, "e.start_time >= "+arg(utc(filter.StartFrom)))
+	}
+	if !filter.StartTo.IsZero() {
+		conditions = append(conditions, "e.start_time < "+arg(utc(filter.StartTo)))
+	}
+
+	order := "DESC"
+	if filter.Order == store.ExecutionOrderOldestFirst {
+		order = "ASC"
+	}
+
+	query := `
+		SELECT ` + executionColumns + `
+		FROM executions e
+		JOIN tasks t ON e.task_id = t.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY e.iteration ` + order
+	if filter.Limit > 0 || filter.Offset > 0 {
+		limit := -1
+		if filter.Limit > 0 {
+			limit = filter.Limit
+		}
+		query += " LIMIT " + arg(limit) + " OFFSET " + arg(filter.Offset)
+	}
+
+	rows, err := es.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	executions := []*store.Execution{}
+	for rows.Next() {
+		execution, err := scanExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+	return executions, rows.Err()
+}
+
+// pruneBatchSize bounds how many rows a single DELETE removes, so pruning a
+// large backlog does not hold locks on the executions table for long.
+const pruneBatchSize = 1000
+
+func (es *executionStore) prune(
+	taskName string, policy *store.RetentionPolicy, now time.Time,
+) (int, error) {
+	query := `
+		DELETE FROM executions
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT
+					e.id,
+					e.start_time,
+					e.manual,
+					ROW_NUMBER() OVER (ORDER BY e.iteration DESC) AS position,
+					ROW_NUMBER() OVER (
+						PARTITION BY e.manual
+						ORDER BY e.iteration DESC
+					) AS manual_position,
+					CASE WHEN e.status IN ('error', 'panic') THEN
+						ROW_NUMBER() OVER (
+							PARTITION BY e.status IN ('error', 'panic')
+							ORDER BY e.iteration DESC
+						)
+					END AS failure_position
+				FROM executions e
+				JOIN tasks t ON e.task_id = t.id
+				WHERE t.name = ?1
+			) ranked
+			WHERE (manual OR manual_position > 1)
+				AND ((?2 AND start_time < ?3) OR (?4 > 0 AND position > ?4))
+				AND (failure_position IS NULL OR failure_position > ?5)
+			LIMIT ?6
+		);
+	`
+
+	cutoff := now.Add(-policy.MaxAge)
+	total := 0
+	for {
+		result, err := es.db.Exec(
+			query,
+			taskName,
+			policy.MaxAge > 0,
+			utc(cutoff),
+			policy.MaxRows,
+			policy.KeepFailures,
+			pruneBatchSize,
+		)
+		if err != nil {
+			return total, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+
+		total += int(affected)
+		if affected < pruneBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func newExecutionStore(db DB) *executionStore {
+	return &executionStore{db: db}
+}
+
+// utc normalizes times before they reach the database: SQLite stores them as
+// text, so comparisons are only meaningful when every value shares a zone.
+func utc(t time.Time) time.Time { return t.UTC() }
@@ -0,0 +1,69 @@
+package sqlite
+
+import "time"
+
+type nodeStore struct {
+	db DB
+}
+
+func (ns *nodeStore) createStore() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS nodes (
+			id         TEXT       PRIMARY KEY,
+			last_seen  TIMESTAMP  NOT NULL
+		);
+	`
+	_, err := ns.db.Exec(query)
+	return err
+}
+
+func (ns *nodeStore) deleteStore() error {
+	query := "DROP TABLE IF EXISTS nodes;"
+	_, err := ns.db.Exec(query)
+	return err
+}
+
+func (ns *nodeStore) clearStore() error {
+	query := "DELETE FROM nodes;"
+	_, err := ns.db.Exec(query)
+	return err
+}
+
+func (ns *nodeStore) heartbeat(nodeID string) error {
+	query := `
+		INSERT INTO nodes (id, last_seen)
+		VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET last_seen = excluded.last_seen;
+	`
+	_, err := ns.db.Exec(query, nodeID, utc(time.Now()))
+	return err
+}
+
+func (ns *nodeStore) list(since time.Time) ([]string, error) {
+	query := "SELECT id FROM nodes WHERE last_seen >= ? ORDER BY id;"
+	rows, err := ns.db.Query(query, utc(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, id)
+	}
+	return nodes, rows.Err()
+}
+
+func (ns *nodeStore) remove(nodeID string) error {
+	query := "DELETE FROM nodes WHERE id = ?;"
+	_, err := ns.db.Exec(query, nodeID)
+	return err
+}
+
+func newNodeStore(db DB) *nodeStore {
+	return &nodeStore{db: db}
+}
@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+type DB interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// expectAffected returns errNone when result reports that no row was
+// touched, which is how missing or conflicting rows surface in this store.
+func expectAffected(result sql.Result, errNone error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errNone
+	}
+	return nil
+}
+
+// column is a column added to a table after its first release.
+type column struct {
+	name       string
+	definition string
+}
+
+// addColumns adds the columns that table is missing, so databases created
+// by older versions pick up the current schema. SQLite has no
+// ADD COLUMN IF NOT EXISTS, hence the lookup in table_info first.
+func addColumns(db DB, table string, columns []column) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?);", table)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	// Close before altering: the store may run on a single connection.
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range columns {
+		if existing[c.name] {
+			continue
+		}
+		query := "ALTER TABLE " + table + " ADD COLUMN " + c.name + " " + c.definition + ";"
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ store.Store = (*SQLiteStore)(nil)
+
+type SQLiteStore struct {
+	taskStore      *taskStore
+	executionStore *executionStore
+	claimStore     *claimStore
+	nodeStore      *nodeStore
+}
+
+func (ss *SQLiteStore) CreateStores() error {
+	if err := ss.taskStore.createStore(); err != nil {
+		return err
+	}
+	if err := ss.executionStore.createStore(); err != nil {
+		return err
+	}
+	if err := ss.claimStore.createStore(); err != nil {
+		return err
+	}
+	if err := ss.nodeStore.createStore(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ss *SQLiteStore) DeleteStores() error {
+	if err := ss.nodeStore.deleteStore(); err != nil {
+		return err
+	}
+	if err := ss.claimStore.deleteStore(); err != nil {
+		return err
+	}
+	if err := ss.executionStore.deleteStore(); err != nil {
+		return err
+	}
+	if err := ss.taskStore.deleteStore(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ss *SQLiteStore) ClearStores() error {
+	if err := ss.nodeStore.clearStore(); err != nil {
+		return err
+	}
+	if err := ss.claimStore.clearStore(); err != nil {
+		return err
+	}
+	if err := ss.executionStore.clearStore(); err != nil {
+		return err
+	}
+	if err := ss.taskStore.clearStore(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ss *SQLiteStore) TaskExists(name string) (bool, error) {
+	return ss.taskStore.exists(name)
+}
+
+func (ss *SQLiteStore) SaveTask(name string, settings *store.TaskSettings) error {
+	return ss.taskStore.save(name, settings)
+}
+
+func (ss *SQLiteStore) GetTaskSettings(name string) (*store.TaskSettings, error) {
+	return ss.taskStore.getSettings(name)
+}
+
+func (ss *SQLiteStore) UpdateTaskStatus(name string, status store.TaskStatus) error {
+	return ss.taskStore.updateStatus(name, status)
+}
+
+func (ss *SQLiteStore) GetTaskStatus(name string) (store.TaskStatus, error) {
+	return ss.taskStore.getStatus(name)
+}
+
+func (ss *SQLiteStore) SaveExecution(name string, info *store.ExecutionInfo) error {
+	taskID, iteration, err := ss.taskStore.increaseIteration(name)
+	if err != nil {
+		return err
+	}
+
+	execution := &store.Execution{
+		ExecutionInfo: info,
+		TaskID:        taskID,
+		Iteration:     iteration,
+	}
+
+	return ss.executionStore.save(execution)
+}
+
+func (ss *SQLiteStore) GetLastTick(name string) (time.Time, error) {
+	return ss.executionStore.getLastTick(name)
+}
+
+func (ss *SQLiteStore) GetExecution(name string, iteration int) (*store.Execution, error) {
+	execution, err := ss.executionStore.get(name, iteration)
+	if errors.Is(err, store.ErrExecutionNotFound) {
+		return nil, ss.taskStore.notFound(name, err)
+	}
+	return execution, err
+}
+
+func (ss *SQLiteStore) ListExecutions(
+	name string, filter *store.ExecutionFilter,
+) ([]*store.Execution, error) {
+	executions, err := ss.executionStore.list(name, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(executions) == 0 {
+		if err := ss.taskStore.notFound(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return executions, nil
+}
+
+func (ss *SQLiteStore) PruneExecutions(
+	name string, policy *store.RetentionPolicy,
+) (int, error) {
+	now := time.Now()
+	pruned, err := ss.executionStore.prune(name, policy, now)
+	if err != nil {
+		return pruned, err
+	}
+	if policy.MaxAge > 0 {
+		if err := ss.claimStore.prune(name, now.Add(-policy.MaxAge)); err != nil {
+			return pruned, err
+		}
+	}
+	if pruned == 0 {
+		if err := ss.taskStore.notFound(name, nil); err != nil {
+			return 0, err
+		}
+	}
+	return pruned, nil
+}
+
+func (ss *SQLiteStore) ClaimTick(name string, tick time.Time, owner string) (bool, error) {
+	return ss.claimStore.claim(name, tick, owner)
+}
+
+func (ss *SQLiteStore) GetOccurrences(name string) (int, error) {
+	return ss.taskStore.getOccurrences(name)
+}
+
+func (ss *SQLiteStore) IncrementOccurrences(name string) (int, error) {
+	return ss.taskStore.increaseOccurrences(name)
+}
+
+func (ss *SQLiteStore) Heartbeat(nodeID string) error {
+	return ss.nodeStore.heartbeat(nodeID)
+}
+
+func (ss *SQLiteStore) ListNodes(since time.Time) ([]string, error) {
+	return ss.nodeStore.list(since)
+}
+
+func (ss *SQLiteStore) RemoveNode(nodeID string) error {
+	return ss.nodeStore.remove(nodeID)
+}
+
+func NewStore(db DB) *SQLiteStore {
+	return &SQLiteStore{
+		taskStore:      newTaskStore(db),
+		executionStore: newExecutionStore(db),
+		claimStore:     newClaimStore(db),
+		nodeStore:      newNodeStore(db),
+	}
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/storetest"
+
+	_ "modernc.org/sqlite"
+)
+
+// baselineSchema is the schema of the first release of this store, before
+// any column was added to its tables.
+const baselineSchema = `
+	CREATE TABLE tasks (
+		id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+		name        TEXT       NOT NULL UNIQUE,
+		job         TEXT       NOT NULL,
+		trigger     TEXT       NOT NULL,
+		policy      TEXT       NOT NULL,
+		status      TEXT       NOT NULL DEFAULT 'idle',
+		iteration   INTEGER    NOT NULL DEFAULT 0,
+		created_at  TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE executions (
+		id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+		task_id     INTEGER    NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		iteration   INTEGER    NOT NULL,
+		start_time  TIMESTAMP  NOT NULL,
+		end_time    TIMESTAMP  NOT NULL,
+		duration    INTEGER    NOT NULL,
+		status      TEXT       NOT NULL,
+		tick        TIMESTAMP  NOT NULL,
+		error_msg   TEXT
+	);
+`
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "taskengine.db") + "?_pragma=foreign_keys(1)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// SQLite allows a single writer, so keep database/sql from opening
+	// concurrent connections that would fail with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestStore(t *testing.T, db *sql.DB) *SQLiteStore {
+	t.Helper()
+
+	ss := NewStore(db)
+	if err := ss.CreateStores(); err != nil {
+		t.Fatalf("failed to create stores: %v", err)
+	}
+	return ss
+}
+
+func TestConformance(t *testing.T) {
+	storetest.RunConformance(t, func(t *testing.T) store.Store {
+		return newTestStore(t, openTestDB(t))
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	storetest.RunUpgrade(t, func(t *testing.T) store.Store {
+		db := openTestDB(t)
+		if _, err := db.Exec(baselineSchema); err != nil {
+			t.Fatalf("failed to create baseline schema: %v", err)
+		}
+		return newTestStore(t, db)
+	})
+}
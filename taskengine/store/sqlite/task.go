@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+type taskStore struct {
+	db DB
+}
+
+// addedTaskColumns lists the tasks columns that tables created by older
+// versions may lack.
+var addedTaskColumns = []column{
+	{"occurrences", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+func (ts *taskStore) createStore() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS tasks (
+			id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+			name        TEXT       NOT NULL UNIQUE,
+			job         TEXT       NOT NULL,
+			trigger     TEXT       NOT NULL,
+			policy      TEXT       NOT NULL,
+			status      TEXT       NOT NULL DEFAULT 'idle',
+			iteration   INTEGER    NOT NULL DEFAULT 0,
+			occurrences INTEGER    NOT NULL DEFAULT 0,
+			created_at  TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+	`
+	if _, err := ts.db.Exec(query); err != nil {
+		return err
+	}
+	return addColumns(ts.db, "tasks", addedTaskColumns)
+}
+
+func (ts *taskStore) deleteStore() error {
+	query := "DROP TABLE IF EXISTS tasks;"
+	_, err := ts.db.Exec(query)
+	return err
+}
+
+func (ts *taskStore) clearStore() error {
+	if _, err := ts.db.Exec("DELETE FROM tasks;"); err != nil {
+		return err
+	}
+	query := "DELETE FROM sqlite_sequence WHERE name = 'tasks';"
+	_, err := ts.db.Exec(query)
+	return err
+}
+
+func (ts *taskStore) exists(name string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM tasks WHERE name = ?);"
+	var exists bool
+	err := ts.db.QueryRow(query, name).Scan(&exists)
+	return exists, err
+}
+
+func (ts *taskStore) save(
+	name string, settings *store.TaskSettings,
+) error {
+	query := `
+		INSERT INTO tasks (name, job, trigger, policy)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO NOTHING;
+	`
+	result, err := ts.db.Exec(
+		query, name,
+		settings.Job,
+		settings.Trigger,
+		settings.Policy,
+	)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, store.ErrTaskAlreadyExists)
+}
+
+func (ts *taskStore) getSettings(name string) (*store.TaskSettings, error) {
+	query := `
+		SELECT job, trigger, policy
+		FROM tasks
+		WHERE name = ?;
+	`
+
+	var settings store.TaskSettings
+	err := ts.db.
+		QueryRow(query, name).
+		Scan(&settings.Job, &settings.Trigger, &settings.Policy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (ts *taskStore) updateStatus(
+	name string, status store.TaskStatus,
+) error {
+	query := "UPDATE tasks SET status = ? WHERE name = ?;"
+	result, err := ts.db.Exec(query, status, name)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, store.ErrTaskNotFound)
+}
+
+func (ts *taskStore) getStatus(name string) (store.TaskStatus, error) {
+	query := "SELECT status FROM tasks WHERE name = ?;"
+
+	var status store.TaskStatus
+	err := ts.db.QueryRow(query, name).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", store.ErrTaskNotFound
+	}
+	return status, err
+}
+
+func (ts *taskStore) increaseIteration(name string) (int, int, error) {
+	query := `
+		UPDATE tasks
+		SET iteration = iteration + 1
+		WHERE name = ?
+		RETURNING id, iteration;
+	`
+	var id int
+	var iteration int
+	err := ts.db.QueryRow(query, name).Scan(&id, &iteration)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, store.ErrTaskNotFound
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, iteration, nil
+}
+
+func (ts *taskStore) getOccurrences(name string) (int, error) {
+	query := "SELECT occurrences FROM tasks WHERE name = ?;"
+
+	var occurrences int
+	err := ts.db.QueryRow(query, name).Scan(&occurrences)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, store.ErrTaskNotFound
+	}
+	return occurrences, err
+}
+
+func (ts *taskStore) increaseOccurrences(name string) (int, error) {
+	query := `
+		UPDATE tasks
+		SET occurrences = occurrences + 1
+		WHERE name = ?
+		RETURNING occurrences;
+	`
+	var occurrences int
+	err := ts.db.QueryRow(query, name).Scan(&occurrences)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, store.ErrTaskNotFound
+	}
+	return occurrences, err
+}
+
+// notFound returns ErrTaskNotFound if the task does not exist and err
+// otherwise, so that empty results can tell both situations apart.
+func (ts *taskStore) notFound(name string, err error) error {
+	exists, existsErr := ts.exists(name)
+	if existsErr != nil {
+		return existsErr
+	}
+	if !exists {
+		return store.ErrTaskNotFound
+	}
+	return err
+}
+
+func newTaskStore(db DB) *taskStore {
+	return &taskStore{db: db}
+}
@@ -2,6 +2,9 @@ package store
 
 import "time"
 
+// Store persists tasks and their executions. Methods addressing a task that
+// was never saved return ErrTaskNotFound; storetest.RunConformance pins down
+// the remaining semantics every implementation must follow.
 type Store interface {
 	CreateStores() error
 	DeleteStores() error
@@ -12,5 +15,42 @@ type Store interface {
 	SaveExecution(name string, info *ExecutionInfo) error
 	GetTaskSettings(name string) (*TaskSettings, error)
 	UpdateTaskStatus(name string, status TaskStatus) error
+	GetTaskStatus(name string) (TaskStatus, error)
+	// GetLastTick returns the tick of the most recent execution that was
+	// not triggered manually, or the zero time when there is none.
 	GetLastTick(name string) (time.Time, error)
+
+	// GetExecution returns the execution with the given iteration, or
+	// ErrExecutionNotFound when the task has no such execution.
+	GetExecution(name string, iteration int) (*Execution, error)
+	// ListExecutions returns the executions matching filter; a nil filter
+	// matches every execution of the task.
+	ListExecutions(name string, filter *ExecutionFilter) ([]*Execution, error)
+	// PruneExecutions deletes the executions selected by policy and
+	// returns how many were deleted. With a MaxAge, claims on ticks older
+	// than it are deleted as well.
+	PruneExecutions(name string, policy *RetentionPolicy) (int, error)
+
+	// ClaimTick reserves a tick of the task for owner and reports whether
+	// owner holds it, which includes claims owner made before.
+	ClaimTick(name string, tick time.Time, owner string) (bool, error)
+
+	// GetOccurrences returns how many ticks the trigger of the task fired,
+	// as counted by IncrementOccurrences, which returns the new count.
+	GetOccurrences(name string) (int, error)
+	IncrementOccurrences(name string) (int, error)
+
+	// Heartbeat records that the node is alive.
+	Heartbeat(nodeID string) error
+	// ListNodes returns, sorted, the nodes whose last heartbeat is not
+	// older than since.
+	ListNodes(since time.Time) ([]string, error)
+	// RemoveNode deletes the node from the registry.
+	RemoveNode(nodeID string) error
+}
+
+// Flusher is implemented by stores that buffer writes. Engine.Shutdown
+// flushes them once every execution is saved.
+type Flusher interface {
+	Flush() error
 }
@@ -0,0 +1,979 @@
+// Package storetest provides a conformance suite that every store.Store
+// implementation is expected to pass.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// Factory returns an empty store whose stores have already been created.
+// The factory is called once per subtest.
+type Factory func(t *testing.T) store.Store
+
+var baseTick = time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+
+func RunConformance(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s store.Store)
+	}{
+		{"CreateStoresIsIdempotent", testCreateStoresIsIdempotent},
+		{"TaskExists", testTaskExists},
+		{"SaveTaskTwice", testSaveTaskTwice},
+		{"GetTaskSettings", testGetTaskSettings},
+		{"GetTaskSettingsUnknownTask", testGetTaskSettingsUnknownTask},
+		{"UpdateTaskStatus", testUpdateTaskStatus},
+		{"UpdateTaskStatusUnknownTask", testUpdateTaskStatusUnknownTask},
+		{"GetTaskStatusUnknownTask", testGetTaskStatusUnknownTask},
+		{"SaveExecutionUnknownTask", testSaveExecutionUnknownTask},
+		{"GetLastTickWithoutExecutions", testGetLastTickWithoutExecutions},
+		{"GetLastTickUnknownTask", testGetLastTickUnknownTask},
+		{"GetLastTickFollowsIteration", testGetLastTickFollowsIteration},
+		{"GetExecution", testGetExecution},
+		{"GetExecutionNotFound", testGetExecutionNotFound},
+		{"GetSkippedExecution", testGetSkippedExecution},
+		{"GetExecutionLogs", testGetExecutionLogs},
+		{"LinkedExecutions", testLinkedExecutions},
+		{"ListExecutions", testListExecutions},
+		{"ListExecutionsUnknownTask", testListExecutionsUnknownTask},
+		{"ListExecutionsFilters", testListExecutionsFilters},
+		{"ListExecutionsPagination", testListExecutionsPagination},
+		{"PruneExecutions", testPruneExecutions},
+		{"PruneExecutionsUnknownTask", testPruneExecutionsUnknownTask},
+		{"GetLastTickIgnoresManualExecutions", testGetLastTickIgnoresManualExecutions},
+		{"ClaimTick", testClaimTick},
+		{"ClaimTickUnknownTask", testClaimTickUnknownTask},
+		{"PruneExecutionsPrunesClaims", testPruneExecutionsPrunesClaims},
+		{"Occurrences", testOccurrences},
+		{"Nodes", testNodes},
+		{"ClearStores", testClearStores},
+		{"DeleteStores", testDeleteStores},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newStore(t))
+		})
+	}
+}
+
+// RunUpgrade checks that a store opened over tables created by an older
+// version exposes every field of the current schema. newStore must create
+// the old tables before calling CreateStores.
+func RunUpgrade(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s store.Store)
+	}{
+		{"CreateStoresIsIdempotent", testCreateStoresIsIdempotent},
+		{"GetExecution", testGetExecution},
+		{"GetSkippedExecution", testGetSkippedExecution},
+		{"GetExecutionLogs", testGetExecutionLogs},
+		{"LinkedExecutions", testLinkedExecutions},
+		{"Occurrences", testOccurrences},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newStore(t))
+		})
+	}
+}
+
+func mustSaveTask(t *testing.T, s store.Store, name string) {
+	t.Helper()
+
+	err := s.SaveTask(name, &store.TaskSettings{
+		Job:     "job." + name,
+		Policy:  "serial",
+		Trigger: "Interval(interval=1m0s, runOnStart=false)",
+	})
+	if err != nil {
+		t.Fatalf("failed to save task '%s': %v", name, err)
+	}
+}
+
+func mustSaveExecution(t *testing.T, s store.Store, name string, info *store.ExecutionInfo) {
+	t.Helper()
+
+	if err := s.SaveExecution(name, info); err != nil {
+		t.Fatalf("failed to save execution for task '%s': %v", name, err)
+	}
+}
+
+func newExecutionInfo(tick time.Time, status store.ExecutionStatus) *store.ExecutionInfo {
+	return &store.ExecutionInfo{
+		StartTime: tick,
+		EndTime:   tick.Add(time.Second),
+		Duration:  time.Second,
+		Status:    status,
+		Tick:      tick,
+		Attempt:   1,
+	}
+}
+
+func testCreateStoresIsIdempotent(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	if err := s.CreateStores(); err != nil {
+		t.Fatalf("expected no error creating stores twice, got %v", err)
+	}
+
+	exists, err := s.TaskExists("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Error("expected task to survive a second CreateStores")
+	}
+}
+
+func testTaskExists(t *testing.T, s store.Store) {
+	exists, err := s.TaskExists("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("expected task not to exist before it is saved")
+	}
+
+	mustSaveTask(t, s, "task")
+
+	exists, err = s.TaskExists("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Error("expected task to exist after it is saved")
+	}
+}
+
+func testSaveTaskTwice(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	err := s.SaveTask("task", &store.TaskSettings{Job: "other"})
+	if !errors.Is(err, store.ErrTaskAlreadyExists) {
+		t.Fatalf("expected ErrTaskAlreadyExists, got %v", err)
+	}
+
+	settings, err := s.GetTaskSettings("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Job != "job.task" {
+		t.Errorf("expected original settings to be kept, got job %s", settings.Job)
+	}
+}
+
+func testGetTaskSettings(t *testing.T, s store.Store) {
+	want := store.TaskSettings{
+		Job:     "pkg.Job",
+		Policy:  "skip_if_busy",
+		Trigger: "Cron(expr=* * * * *, runOnStart=false)",
+	}
+	if err := s.SaveTask("task", &want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := s.GetTaskSettings("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *got != want {
+		t.Errorf("expected settings %+v, got %+v", want, *got)
+	}
+}
+
+func testGetTaskSettingsUnknownTask(t *testing.T, s store.Store) {
+	if _, err := s.GetTaskSettings("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testUpdateTaskStatus(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	status, err := s.GetTaskStatus("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != store.TaskStatusIdle {
+		t.Errorf("expected new tasks to be %s, got %s", store.TaskStatusIdle, status)
+	}
+
+	for _, want := range []store.TaskStatus{store.TaskStatusRunning, store.TaskStatusIdle} {
+		if err := s.UpdateTaskStatus("task", want); err != nil {
+			t.Fatalf("expected no error updating status to %s, got %v", want, err)
+		}
+
+		got, err := s.GetTaskStatus("task")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("expected status %s, got %s", want, got)
+		}
+	}
+}
+
+func testUpdateTaskStatusUnknownTask(t *testing.T, s store.Store) {
+	err := s.UpdateTaskStatus("missing", store.TaskStatusRunning)
+	if !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testGetTaskStatusUnknownTask(t *testing.T, s store.Store) {
+	if _, err := s.GetTaskStatus("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testSaveExecutionUnknownTask(t *testing.T, s store.Store) {
+	err := s.SaveExecution("missing", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+	if !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testGetLastTickWithoutExecutions(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	tick, err := s.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("expected no error for a task that never ran, got %v", err)
+	}
+	if !tick.IsZero() {
+		t.Errorf("expected zero tick for a task that never ran, got %v", tick)
+	}
+}
+
+func testGetLastTickUnknownTask(t *testing.T, s store.Store) {
+	if _, err := s.GetLastTick("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testGetLastTickFollowsIteration(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+	mustSaveTask(t, s, "other")
+
+	// The most recently saved execution wins, even if its tick is older.
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick.Add(2*time.Minute), store.ExecutionStatusSuccess))
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick.Add(time.Minute), store.ExecutionStatusError))
+	mustSaveExecution(t, s, "other", newExecutionInfo(baseTick.Add(time.Hour), store.ExecutionStatusSuccess))
+
+	tick, err := s.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := baseTick.Add(time.Minute); !tick.Equal(want) {
+		t.Errorf("expected last tick %v, got %v", want, tick)
+	}
+}
+
+func testGetLastTickIgnoresManualExecutions(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	manual := newExecutionInfo(baseTick.Add(time.Hour), store.ExecutionStatusSuccess)
+	manual.Manual = true
+
+	mustSaveExecution(t, s, "task", manual)
+	tick, err := s.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tick.IsZero() {
+		t.Errorf("expected zero tick when only manual executions exist, got %v", tick)
+	}
+
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+	mustSaveExecution(t, s, "task", manual)
+	tick, err = s.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tick.Equal(baseTick) {
+		t.Errorf("expected last scheduled tick %v, got %v", baseTick, tick)
+	}
+}
+
+func testClearStores(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+
+	if err := s.ClearStores(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exists, err := s.TaskExists("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("expected task to be removed by ClearStores")
+	}
+
+	mustSaveTask(t, s, "task")
+	tick, err := s.GetLastTick("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tick.IsZero() {
+		t.Errorf("expected executions to be removed by ClearStores, got last tick %v", tick)
+	}
+}
+
+func testDeleteStores(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	if err := s.DeleteStores(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.CreateStores(); err != nil {
+		t.Fatalf("unexpected error recreating stores: %v", err)
+	}
+
+	exists, err := s.TaskExists("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("expected task to be removed by DeleteStores")
+	}
+}
+
+func iterations(executions []*store.Execution) []int {
+	result := make([]int, len(executions))
+	for i, e := range executions {
+		result[i] = e.Iteration
+	}
+	return result
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testGetExecution(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	want := &store.ExecutionInfo{
+		StartTime: baseTick.Add(time.Second),
+		EndTime:   baseTick.Add(2500 * time.Millisecond),
+		Duration:  1500 * time.Millisecond,
+		Status:    store.ExecutionStatusError,
+		Tick:      baseTick,
+		Attempt:   3,
+		Manual:    true,
+		ErrorMsg:  "boom",
+	}
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+	mustSaveExecution(t, s, "task", want)
+
+	got, err := s.GetExecution("task", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Iteration != 2 {
+		t.Errorf("expected iteration 2, got %d", got.Iteration)
+	}
+	if !got.StartTime.Equal(want.StartTime) || !got.EndTime.Equal(want.EndTime) {
+		t.Errorf("expected times %v-%v, got %v-%v", want.StartTime, want.EndTime, got.StartTime, got.EndTime)
+	}
+	if got.Duration != want.Duration {
+		t.Errorf("expected duration %s, got %s", want.Duration, got.Duration)
+	}
+	if got.Status != want.Status {
+		t.Errorf("expected status %s, got %s", want.Status, got.Status)
+	}
+	if !got.Tick.Equal(want.Tick) {
+		t.Errorf("expected tick %v, got %v", want.Tick, got.Tick)
+	}
+	if got.Attempt != want.Attempt {
+		t.Errorf("expected attempt %d, got %d", want.Attempt, got.Attempt)
+	}
+	if got.Manual != want.Manual {
+		t.Errorf("expected manual %v, got %v", want.Manual, got.Manual)
+	}
+	if got.ErrorMsg != want.ErrorMsg {
+		t.Errorf("expected error message %q, got %q", want.ErrorMsg, got.ErrorMsg)
+	}
+}
+
+func testGetSkippedExecution(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	info := newExecutionInfo(baseTick, store.ExecutionStatusSkipped)
+	info.SkipReason = store.SkipReasonStale
+	info.MissedTicks = 3
+	mustSaveExecution(t, s, "task", info)
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+
+	skipped, err := s.GetExecution("task", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skipped.SkipReason != store.SkipReasonStale {
+		t.Errorf("expected skip reason %s, got %q", store.SkipReasonStale, skipped.SkipReason)
+	}
+	if skipped.MissedTicks != 3 {
+		t.Errorf("expected 3 missed ticks, got %d", skipped.MissedTicks)
+	}
+
+	success, err := s.GetExecution("task", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if success.SkipReason != "" || success.MissedTicks != 0 {
+		t.Errorf("expected no skip reason, got %q and %d missed ticks", success.SkipReason, success.MissedTicks)
+	}
+}
+
+func testGetExecutionLogs(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	logs := "[INFO] first line\n[ERROR] second line attempt=1\n"
+	info := newExecutionInfo(baseTick, store.ExecutionStatusError)
+	info.Logs = logs
+	mustSaveExecution(t, s, "task", info)
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+
+	tests := []struct {
+		iteration int
+		expected  string
+	}{
+		{1, logs},
+		{2, ""},
+	}
+
+	for _, tc := range tests {
+		execution, err := s.GetExecution("task", tc.iteration)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if execution.Logs != tc.expected {
+			t.Errorf("iteration %d: expected logs %q, got %q", tc.iteration, tc.expected, execution.Logs)
+		}
+	}
+
+	executions, err := s.ListExecutions("task", &store.ExecutionFilter{Order: store.ExecutionOrderOldestFirst})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executions) != 2 || executions[0].Logs != logs {
+		t.Errorf("expected listed executions to carry their logs")
+	}
+}
+
+func testLinkedExecutions(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "workflow")
+	mustSaveTask(t, s, "task")
+
+	parent := newExecutionInfo(baseTick, store.ExecutionStatusSuccess)
+	parent.ExecutionID = "parent"
+	mustSaveExecution(t, s, "workflow", parent)
+
+	for i, parentID := range []string{"parent", "other", "parent"} {
+		info := newExecutionInfo(baseTick, store.ExecutionStatusSuccess)
+		info.ExecutionID = fmt.Sprintf("child-%d", i)
+		info.ParentExecutionID = parentID
+		mustSaveExecution(t, s, "task", info)
+	}
+	mustSaveExecution(t, s, "task", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+
+	execution, err := s.GetExecution("workflow", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if execution.ExecutionID != "parent" || execution.ParentExecutionID != "" {
+		t.Errorf("expected execution id parent without parent, got %q and %q", execution.ExecutionID, execution.ParentExecutionID)
+	}
+
+	executions, err := s.ListExecutions("task", &store.ExecutionFilter{
+		ParentExecutionID: "parent",
+		Order:             store.ExecutionOrderOldestFirst,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executions) != 2 || executions[0].ExecutionID != "child-0" || executions[1].ExecutionID != "child-2" {
+		t.Fatalf("expected children 0 and 2 of parent, got %d executions", len(executions))
+	}
+	if executions[0].ParentExecutionID != "parent" {
+		t.Errorf("expected parent execution id parent, got %q", executions[0].ParentExecutionID)
+	}
+}
+
+func testGetExecutionNotFound(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	if _, err := s.GetExecution("task", 1); !errors.Is(err, store.ErrExecutionNotFound) {
+		t.Errorf("expected ErrExecutionNotFound, got %v", err)
+	}
+
+	if _, err := s.GetExecution("missing", 1); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testListExecutions(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+	mustSaveTask(t, s, "other")
+
+	executions, err := s.ListExecutions("task", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executions == nil || len(executions) != 0 {
+		t.Errorf("expected empty non-nil result for a task that never ran, got %v", executions)
+	}
+
+	for i := 0; i < 3; i++ {
+		mustSaveExecution(t, s, "task", newExecutionInfo(baseTick.Add(time.Duration(i)*time.Minute), store.ExecutionStatusSuccess))
+	}
+	mustSaveExecution(t, s, "other", newExecutionInfo(baseTick, store.ExecutionStatusSuccess))
+
+	executions, err = s.ListExecutions("task", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := iterations(executions), []int{3, 2, 1}; !equalInts(got, want) {
+		t.Errorf("expected iterations %v, got %v", want, got)
+	}
+
+	executions, err = s.ListExecutions("task", &store.ExecutionFilter{Order: store.ExecutionOrderOldestFirst})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := iterations(executions), []int{1, 2, 3}; !equalInts(got, want) {
+		t.Errorf("expected iterations %v, got %v", want, got)
+	}
+}
+
+func testListExecutionsUnknownTask(t *testing.T, s store.Store) {
+	if _, err := s.ListExecutions("missing", nil); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testListExecutionsFilters(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	statuses := []store.ExecutionStatus{
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusError,
+		store.ExecutionStatusPanic,
+		store.ExecutionStatusSkipped,
+		store.ExecutionStatusSuccess,
+	}
+	for i, status := range statuses {
+		mustSaveExecution(t, s, "task", newExecutionInfo(baseTick.Add(time.Duration(i)*time.Minute), status))
+	}
+
+	tests := []struct {
+		name   string
+		filter store.ExecutionFilter
+		want   []int
+	}{
+		{
+			name:   "single status",
+			filter: store.ExecutionFilter{Statuses: []store.ExecutionStatus{store.ExecutionStatusSuccess}},
+			want:   []int{5, 1},
+		},
+		{
+			name: "several statuses",
+			filter: store.ExecutionFilter{Statuses: []store.ExecutionStatus{
+				store.ExecutionStatusError, store.ExecutionStatusPanic,
+			}},
+			want: []int{3, 2},
+		},
+		{
+			name:   "tick range includes from and excludes to",
+			filter: store.ExecutionFilter{TickFrom: baseTick.Add(time.Minute), TickTo: baseTick.Add(3 * time.Minute)},
+			want:   []int{3, 2},
+		},
+		{
+			name:   "start range",
+			filter: store.ExecutionFilter{StartFrom: baseTick.Add(3 * time.Minute)},
+			want:   []int{5, 4},
+		},
+		{
+			name: "combined",
+			filter: store.ExecutionFilter{
+				Statuses: []store.ExecutionStatus{store.ExecutionStatusSuccess},
+				StartTo:  baseTick.Add(4 * time.Minute),
+			},
+			want: []int{1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			executions, err := s.ListExecutions("task", &tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := iterations(executions); !equalInts(got, tc.want) {
+				t.Errorf("expected iterations %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func testListExecutionsPagination(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+	for i := 0; i < 5; i++ {
+		mustSaveExecution(t, s, "task", newExecutionInfo(baseTick.Add(time.Duration(i)*time.Minute), store.ExecutionStatusSuccess))
+	}
+
+	tests := []struct {
+		name   string
+		filter store.ExecutionFilter
+		want   []int
+	}{
+		{"limit", store.ExecutionFilter{Limit: 2}, []int{5, 4}},
+		{"limit and offset", store.ExecutionFilter{Limit: 2, Offset: 2}, []int{3, 2}},
+		{"offset only", store.ExecutionFilter{Offset: 3}, []int{2, 1}},
+		{"offset past end", store.ExecutionFilter{Offset: 10}, []int{}},
+		{"oldest first", store.ExecutionFilter{Limit: 2, Offset: 1, Order: store.ExecutionOrderOldestFirst}, []int{2, 3}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			executions, err := s.ListExecutions("task", &tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := iterations(executions); !equalInts(got, tc.want) {
+				t.Errorf("expected iterations %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func testPruneExecutions(t *testing.T, s store.Store) {
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-48 * time.Hour)
+
+	// Iterations 1-6 ran two days ago, 7-10 ran just now.
+	statuses := []store.ExecutionStatus{
+		store.ExecutionStatusError,
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusPanic,
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusError,
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusError,
+		store.ExecutionStatusSuccess,
+		store.ExecutionStatusSuccess,
+	}
+
+	tests := []struct {
+		name   string
+		policy store.RetentionPolicy
+		manual bool
+		pruned int
+		want   []int
+	}{
+		{
+			name:   "empty policy",
+			policy: store.RetentionPolicy{},
+			pruned: 0,
+			want:   []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+		},
+		{
+			name:   "max age",
+			policy: store.RetentionPolicy{MaxAge: 24 * time.Hour},
+			pruned: 6,
+			want:   []int{10, 9, 8, 7},
+		},
+		{
+			name:   "max rows",
+			policy: store.RetentionPolicy{MaxRows: 3},
+			pruned: 7,
+			want:   []int{10, 9, 8},
+		},
+		{
+			name:   "max age keeping failures",
+			policy: store.RetentionPolicy{MaxAge: 24 * time.Hour, KeepFailures: 3},
+			pruned: 4,
+			want:   []int{10, 9, 8, 7, 5, 3},
+		},
+		{
+			name:   "max rows or max age",
+			policy: store.RetentionPolicy{MaxAge: 24 * time.Hour, MaxRows: 2},
+			pruned: 8,
+			want:   []int{10, 9},
+		},
+		{
+			name:   "latest execution is always kept",
+			policy: store.RetentionPolicy{MaxAge: time.Nanosecond, MaxRows: 0},
+			pruned: 9,
+			want:   []int{10},
+		},
+		{
+			name:   "latest scheduled execution is kept after a manual run",
+			policy: store.RetentionPolicy{MaxRows: 1},
+			manual: true,
+			pruned: 9,
+			want:   []int{11, 10},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := s.ClearStores(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			mustSaveTask(t, s, "task")
+			mustSaveTask(t, s, "other")
+
+			for i, status := range statuses {
+				start := old
+				if i >= 6 {
+					start = now
+				}
+				info := newExecutionInfo(start, status)
+				mustSaveExecution(t, s, "task", info)
+				mustSaveExecution(t, s, "other", info)
+			}
+			if tc.manual {
+				info := newExecutionInfo(now.Add(time.Minute), store.ExecutionStatusSuccess)
+				info.Manual = true
+				mustSaveExecution(t, s, "task", info)
+				mustSaveExecution(t, s, "other", info)
+			}
+
+			pruned, err := s.PruneExecutions("task", &tc.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pruned != tc.pruned {
+				t.Errorf("expected %d pruned executions, got %d", tc.pruned, pruned)
+			}
+
+			executions, err := s.ListExecutions("task", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := iterations(executions); !equalInts(got, tc.want) {
+				t.Errorf("expected remaining iterations %v, got %v", tc.want, got)
+			}
+
+			tick, err := s.GetLastTick("task")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tick.Equal(now) {
+				t.Errorf("expected last scheduled tick %v to survive pruning, got %v", now, tick)
+			}
+
+			others, err := s.ListExecutions("other", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wantOthers := len(statuses)
+			if tc.manual {
+				wantOthers++
+			}
+			if len(others) != wantOthers {
+				t.Errorf("expected executions of other tasks to be kept, got %d", len(others))
+			}
+		})
+	}
+}
+
+func testPruneExecutionsUnknownTask(t *testing.T, s store.Store) {
+	_, err := s.PruneExecutions("missing", &store.RetentionPolicy{MaxRows: 1})
+	if !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testClaimTick(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+	mustSaveTask(t, s, "other")
+
+	tests := []struct {
+		name     string
+		task     string
+		tick     time.Time
+		owner    string
+		expected bool
+	}{
+		{"first claim", "task", baseTick, "node-a", true},
+		{"claimed elsewhere", "task", baseTick, "node-b", false},
+		{"claimed again by owner", "task", baseTick, "node-a", true},
+		{"same instant in another zone", "task", baseTick.In(time.FixedZone("UTC+2", 2*60*60)), "node-b", false},
+		{"another tick", "task", baseTick.Add(time.Minute), "node-b", true},
+		{"another task", "other", baseTick, "node-b", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claimed, err := s.ClaimTick(tc.task, tc.tick, tc.owner)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claimed != tc.expected {
+				t.Errorf("expected claimed %v, got %v", tc.expected, claimed)
+			}
+		})
+	}
+}
+
+func testClaimTickUnknownTask(t *testing.T, s store.Store) {
+	_, err := s.ClaimTick("missing", baseTick, "node-a")
+	if !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func testPruneExecutionsPrunesClaims(t *testing.T, s store.Store) {
+	mustSaveTask(t, s, "task")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-48 * time.Hour)
+	for _, tick := range []time.Time{old, now} {
+		if _, err := s.ClaimTick("task", tick, "node-a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := s.PruneExecutions("task", &store.RetentionPolicy{MaxAge: 24 * time.Hour}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tick     time.Time
+		expected bool
+	}{
+		{"expired claim is released", old, true},
+		{"recent claim is kept", now, false},
+	}
+
+	for _, tc := range tests {
+		claimed, err := s.ClaimTick("task", tc.tick, "node-b")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if claimed != tc.expected {
+			t.Errorf("%s: expected claimed %v, got %v", tc.name, tc.expected, claimed)
+		}
+	}
+}
+
+func testOccurrences(t *testing.T, s store.Store) {
+	if _, err := s.GetOccurrences("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+	if _, err := s.IncrementOccurrences("missing"); !errors.Is(err, store.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	mustSaveTask(t, s, "task")
+	mustSaveTask(t, s, "other")
+
+	occurrences, err := s.GetOccurrences("task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if occurrences != 0 {
+		t.Errorf("expected 0 occurrences for a new task, got %d", occurrences)
+	}
+
+	for expected := 1; expected <= 2; expected++ {
+		occurrences, err := s.IncrementOccurrences("task")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if occurrences != expected {
+			t.Errorf("expected %d occurrences, got %d", expected, occurrences)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected int
+	}{
+		{"task", 2},
+		{"other", 0},
+	}
+
+	for _, tc := range tests {
+		occurrences, err := s.GetOccurrences(tc.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if occurrences != tc.expected {
+			t.Errorf("%s: expected %d occurrences, got %d", tc.name, tc.expected, occurrences)
+		}
+	}
+}
+
+func testNodes(t *testing.T, s store.Store) {
+	before := time.Now().Add(-time.Minute)
+	for _, id := range []string{"node-b", "node-a"} {
+		if err := s.Heartbeat(id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	since := time.Now()
+	if err := s.Heartbeat("node-b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		remove string
+		since  time.Time
+		want   []string
+	}{
+		{"all nodes", "", before, []string{"node-a", "node-b"}},
+		{"recent heartbeats", "", since, []string{"node-b"}},
+		{"future", "", time.Now().Add(time.Minute), []string{}},
+		{"removed node", "node-a", before, []string{"node-b"}},
+		{"unknown node", "node-c", before, []string{"node-b"}},
+	}
+
+	for _, tc := range tests {
+		if tc.remove != "" {
+			if err := s.RemoveNode(tc.remove); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+		}
+
+		nodes, err := s.ListNodes(tc.since)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if len(nodes) != len(tc.want) {
+			t.Errorf("%s: expected nodes %v, got %v", tc.name, tc.want, nodes)
+			continue
+		}
+		for i := range nodes {
+			if nodes[i] != tc.want[i] {
+				t.Errorf("%s: expected nodes %v, got %v", tc.name, tc.want, nodes)
+				break
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package taskengine
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 )
@@ -11,8 +12,25 @@ type workerSupervisorState int
 const (
 	workerSupervisorIdle workerSupervisorState = iota
 	workerSupervisorRunning
+	// workerSupervisorCompleted: the trigger has no more ticks and every
+	// dispatched tick was executed. A completed supervisor is never started
+	// again, not even after Shutdown.
+	workerSupervisorCompleted
 )
 
+func (s workerSupervisorState) String() string {
+	switch s {
+	case workerSupervisorIdle:
+		return "idle"
+	case workerSupervisorRunning:
+		return "running"
+	case workerSupervisorCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
 type WorkerSupervisor struct {
 	wg sync.WaitGroup
 
@@ -24,11 +42,22 @@ type WorkerSupervisor struct {
 
 	state atomic.Value
 
+	// mu orders enqueue against Shutdown and completion, so a tick handed
+	// to the supervisor is either run by the worker or rejected.
+	mu        sync.Mutex
+	accepting bool
+
 	logger Logger
 }
 
+func (ws *WorkerSupervisor) Status() workerSupervisorState {
+	return ws.state.Load().(workerSupervisorState)
+}
+
 func (ws *WorkerSupervisor) WorkerStatus() workerState { return ws.worker.Status() }
 
+func (ws *WorkerSupervisor) InFlight() int { return ws.worker.InFlight() }
+
 func (ws *WorkerSupervisor) SchedulerStatus() schedulerState { return ws.scheduler.Status() }
 
 func (ws *WorkerSupervisor) PauseScheduler() { ws.scheduler.Pause() }
@@ -37,16 +66,67 @@ func (ws *WorkerSupervisor) ResumeScheduler() { ws.scheduler.Resume() }
 
 func (ws *WorkerSupervisor) Shutdown() {
 	if ws.shutdown != nil {
+		ws.setAccepting(false)
 		ws.shutdown()
 		ws.shutdown = nil
-		ws.dispatcher.Close()
 
 		ws.wg.Wait()
-		ws.state.Store(workerSupervisorIdle)
+
+		// Leftovers are dropped so that a restarted supervisor resumes
+		// from the last tick in the store, like a freshly registered one.
+		drainDispatcher(ws.dispatcher, ws.worker.task)
+		ws.scheduler.dropCommands()
+		ws.state.CompareAndSwap(workerSupervisorRunning, workerSupervisorIdle)
+		ws.publish(EventSupervisorStopped)
+	}
+}
+
+func (ws *WorkerSupervisor) setAccepting(accepting bool) {
+	ws.mu.Lock()
+	ws.accepting = accepting
+	ws.mu.Unlock()
+}
+
+// enqueue hands tick to the worker. It fails with ErrorTaskNotRunning once
+// the supervisor is shutting down or completing, because the tick would
+// otherwise be drained without running.
+func (ws *WorkerSupervisor) enqueue(tick *Tick) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if !ws.accepting {
+		return ErrorTaskNotRunning
+	}
+	return ws.dispatcher.Enqueue(tick)
+}
+
+func (ws *WorkerSupervisor) publish(eventType EventType) {
+	task := ws.worker.task
+	task.events.publish(Event{Type: eventType, Task: task.name})
+}
+
+func drainDispatcher(d Dispatcher, task *Task) {
+	for {
+		select {
+		case tick, ok := <-d.Dequeue():
+			if !ok {
+				return
+			}
+			task.settle(tick, false)
+		default:
+			return
+		}
 	}
 }
 
-func (ws *WorkerSupervisor) Start(ctx context.Context) {
+func (ws *WorkerSupervisor) Start(ctx context.Context) { ws.start(ctx, false) }
+
+func (ws *WorkerSupervisor) start(ctx context.Context, paused bool) {
+	if ws.state.Load().(workerSupervisorState) == workerSupervisorCompleted {
+		ws.logger.Error("WorkerSupervisor is completed, cannot start again")
+		return
+	}
+
 	if ws.state.Load().(workerSupervisorState) != workerSupervisorIdle {
 		ws.logger.Error("WorkerSupervisor is already running, cannot start again")
 		return
@@ -54,12 +134,38 @@ func (ws *WorkerSupervisor) Start(ctx context.Context) {
 
 	ctx, cancel := context.WithCancel(ctx)
 	ws.shutdown = cancel
+	ws.state.Store(workerSupervisorRunning)
+	ws.setAccepting(true)
+
+	finish := make(chan struct{})
+	workerDone := make(chan struct{})
 
 	ws.wg.Add(1)
-	go func() { defer ws.wg.Done(); ws.worker.Run(ctx) }()
+	go func() {
+		defer ws.wg.Done()
+		defer close(workerDone)
+		ws.worker.run(ctx, finish)
+	}()
 
+	ws.scheduler.listen()
 	ws.wg.Add(1)
-	go func() { defer ws.wg.Done(); ws.scheduler.Run(ctx) }()
+	go func() {
+		defer ws.wg.Done()
+		err := ws.scheduler.run(ctx, paused)
+		if !errors.Is(err, ErrorNoMoreTicks) {
+			return
+		}
+
+		ws.setAccepting(false)
+		close(finish)
+		<-workerDone
+		if ws.state.CompareAndSwap(workerSupervisorRunning, workerSupervisorCompleted) {
+			ws.logger.Infof("Task '%s' completed", ws.worker.task.name)
+			ws.publish(EventSupervisorCompleted)
+		}
+	}()
+
+	ws.publish(EventSupervisorStarted)
 }
 
 func newWorkerSupervisor(
@@ -2,6 +2,8 @@ package taskengine
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
@@ -21,112 +23,334 @@ type Task struct {
 
 	logger  Logger
 	timeout time.Duration
+	retry   *RetryPolicy
+
+	maxConcurrency      int
+	concurrencyOverflow concurrencyOverflow
+
+	exactlyOnce bool
+	nodeID      string
+
+	logCaptureLimit int
+
+	hooks      hooks
+	middleware middlewareChain
+
+	// upstream names the workflow tasks this task depends on.
+	upstream []string
+
+	metrics MetricsSink
+	tracer  Tracer
+	policy  workerPolicy
+	events  *eventBus
 
 	store store.Store
 }
 
 func (t *Task) Name() string { return t.name }
 
-func (t *Task) setLogger(factory LoggerFactory) { t.logger = factory(t.name) }
+func (t *Task) setLogger(factory LoggerFactory) {
+	t.logger = factory(t.name).With("task", t.name)
+}
 
 func (t *Task) setStore(store store.Store) { t.store = store }
 
-func (t *Task) Execute(parentCtx context.Context, tick *Tick) {
-	startTime := time.Now()
+func (t *Task) setNodeID(nodeID string) { t.nodeID = nodeID }
 
-	defer func() {
-		if r := recover(); r != nil {
-			endTime := time.Now()
-			duration := endTime.Sub(startTime)
-
-			t.logger.Errorf("PANIC in Task '%s' job: %v", t.name, r)
-
-			err := t.store.SaveExecution(
-				t.name,
-				&store.ExecutionInfo{
-					StartTime: startTime,
-					EndTime:   endTime,
-					Duration:  duration,
-					Status:    store.ExecutionStatusPanic,
-					Tick:      tick.currentTick,
-					ErrorMsg:  fmt.Sprintf("PANIC: %v", r),
-				},
-			)
-
-			if err != nil {
-				t.logger.Errorf(
-					"Failed to save execution info for task '%s': %v",
-					t.name, err,
-				)
-			}
+func (t *Task) setMetrics(metrics MetricsSink) { t.metrics = metrics }
+
+func (t *Task) setTracer(tracer Tracer) { t.tracer = tracer }
+
+func (t *Task) setPolicy(policy workerPolicy) { t.policy = policy }
+
+func (t *Task) setEvents(events *eventBus) { t.events = events }
+
+var executionEvents = map[store.ExecutionStatus]EventType{
+	store.ExecutionStatusSuccess: EventExecutionSucceeded,
+	store.ExecutionStatusError:   EventExecutionFailed,
+	store.ExecutionStatusPanic:   EventExecutionPanicked,
+	store.ExecutionStatusSkipped: EventTickDropped,
+}
+
+func (t *Task) saveExecution(tick *Tick, info *store.ExecutionInfo) {
+	t.metrics.ObserveExecution(
+		t.name, info.Status, info.Duration, info.StartTime.Sub(info.Tick),
+	)
+
+	err := t.store.SaveExecution(t.name, info)
+	if err != nil {
+		t.logger.Errorf(
+			"Failed to save execution info for task '%s': %v",
+			t.name, err,
+		)
+	}
+
+	event := tickEvent(executionEvents[info.Status], t.name, tick)
+	event.Attempt = info.Attempt
+	event.Execution = info
+	t.events.publish(event)
+}
+
+// skip saves a tick that was dropped without running the job.
+func (t *Task) skip(tick *Tick, reason store.SkipReason) {
+	t.settle(tick, false)
+	t.saveExecution(tick, skippedExecution(tick, reason))
+}
+
+// settle counts tick as an occurrence of the trigger of the task when it
+// ran. Ticks whose trigger does not count occurrences, or already settled,
+// are left alone.
+func (t *Task) settle(tick *Tick, ran bool) {
+	if tick.counter == nil {
+		return
+	}
+	counter := tick.counter
+	tick.counter = nil
+
+	if err := counter.settle(t.name, t.store, ran); err != nil {
+		t.logger.Errorf("Failed to count tick of task '%s': %v", t.name, err)
+	}
+}
+
+// skipMissed records missed consecutive past ticks, the latest of which is
+// tick, as a single skipped execution.
+func (t *Task) skipMissed(tick *Tick, reason store.SkipReason, missed int) {
+	info := skippedExecution(tick, reason)
+	info.MissedTicks = missed
+	t.saveExecution(tick, info)
+}
+
+func skippedExecution(tick *Tick, reason store.SkipReason) *store.ExecutionInfo {
+	now := time.Now()
+	return &store.ExecutionInfo{
+		StartTime:  now,
+		EndTime:    now,
+		Status:     store.ExecutionStatusSkipped,
+		Tick:       tick.currentTick,
+		Manual:     tick.manual,
+		SkipReason: reason,
+
+		ParentExecutionID: tick.parentExecutionID,
+	}
+}
+
+// claim reserves a scheduled tick for this node in the store. Ticks that are
+// claimed elsewhere, or whose claim fails, are not executed.
+func (t *Task) claim(tick *Tick) bool {
+	claimed, err := t.store.ClaimTick(t.name, tick.currentTick, t.nodeID)
+	if err != nil {
+		t.logger.Errorf(
+			"Failed to claim tick %s of task '%s': %v",
+			tick.currentTick.Format("2006-01-02 15:04:05"), t.name, err,
+		)
+		return false
+	}
+	if !claimed {
+		t.logger.Infof(
+			"Tick %s of task '%s' was claimed by another node",
+			tick.currentTick.Format("2006-01-02 15:04:05"), t.name,
+		)
+	}
+	return claimed
+}
+
+func (t *Task) Execute(parentCtx context.Context, tick *Tick) { t.execute(parentCtx, tick) }
+
+// execute runs the job, retrying it as configured, and returns the status of
+// the last attempt, or an empty status when the job did not run. Once the job
+// is done retrying, the last attempt is published as the completed execution.
+func (t *Task) execute(parentCtx context.Context, tick *Tick) store.ExecutionStatus {
+	// Workflow tasks are not claimed: the tick of their workflow is.
+	claimed := tick.manual || tick.parentExecutionID != ""
+	if t.exactlyOnce && !claimed && !t.claim(tick) {
+		t.settle(tick, false)
+		return ""
+	}
+	t.settle(tick, true)
+
+	executionID := newExecutionID()
+	logger := t.logger.With("tick", tick.currentTick, "execution_id", executionID)
+	firstStart := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		info, err := t.executeAttempt(parentCtx, tick, attempt, executionID, logger)
+		if err == nil {
+			return t.complete(tick, info)
 		}
-	}()
+
+		delay, retry := t.retry.nextDelay(attempt, err, time.Since(firstStart))
+		if !retry {
+			return t.complete(tick, info)
+		}
+
+		logger.Warnf(
+			"Retrying Task '%s' in %s (attempt %d failed)",
+			t.name, delay, attempt,
+		)
+		select {
+		case <-time.After(delay):
+		case <-parentCtx.Done():
+			return t.complete(tick, info)
+		}
+	}
+}
+
+func (t *Task) complete(tick *Tick, info *store.ExecutionInfo) store.ExecutionStatus {
+	event := tickEvent(EventExecutionCompleted, t.name, tick)
+	event.Attempt = info.Attempt
+	event.Execution = info
+	t.events.publish(event)
+	return info.Status
+}
+
+// executeAttempt runs the job once and saves the outcome as an execution. It
+// returns the saved execution along with the error of a failed job, and nil
+// when the job succeeded or panicked since panics are never retried. Outcomes
+// are logged by the middlewares of the task.
+func (t *Task) executeAttempt(
+	parentCtx context.Context, tick *Tick, attempt int, executionID string, logger Logger,
+) (*store.ExecutionInfo, error) {
+	startTime := time.Now()
+	logger = logger.With("attempt", attempt)
+
+	spanCtx, span := t.tracer.Start(parentCtx, "taskengine.execute", map[string]any{
+		"task.name":         t.name,
+		"task.tick":         tick.currentTick,
+		"task.attempt":      attempt,
+		"task.policy":       t.policy.String(),
+		"task.manual":       tick.manual,
+		"task.execution_id": executionID,
+	})
+	defer span.End()
+
+	jobLogger, capturedLogs := logger, func() string { return "" }
+	if t.logCaptureLimit > 0 {
+		captured := newCaptureLogger(logger, t.logCaptureLimit)
+		jobLogger, capturedLogs = captured, captured.buffer.String
+	}
 
 	var ctx context.Context
 	var cancel context.CancelFunc
 
 	if t.timeout > 0 {
-		ctx, cancel = context.WithTimeout(parentCtx, t.timeout)
+		ctx, cancel = context.WithTimeout(spanCtx, t.timeout)
 	} else {
-		ctx, cancel = context.WithCancel(parentCtx)
+		ctx, cancel = context.WithCancel(spanCtx)
 	}
 
 	defer cancel()
 
 	ctxTask := Context{
-		ctx:      ctx,
-		tick:     tick,
-		logger:   t.logger,
-		taskName: t.name,
+		ctx:         ctx,
+		tick:        tick,
+		span:        span,
+		executionID: executionID,
+		logger:      jobLogger,
+		attempt:     attempt,
+		taskName:    t.name,
+		taskLogger:  logger,
 	}
 
-	t.logger.Infof("Executing Task '%s'", t.name)
+	event := tickEvent(EventExecutionStarted, t.name, tick)
+	event.Attempt = attempt
+	t.events.publish(event)
+	t.hooks.start(logger, &ctxTask)
 
-	err := t.job(&ctxTask)
+	err := t.runJob(&ctxTask, logger)
 	endTime := time.Now()
 	duration := endTime.Sub(startTime)
 
-	if err != nil {
-		t.logger.Errorf("Task '%s' failed: %v", t.name, err)
-
-		err := t.store.SaveExecution(
-			t.name,
-			&store.ExecutionInfo{
-				StartTime: startTime,
-				EndTime:   endTime,
-				Duration:  duration,
-				Status:    store.ExecutionStatusError,
-				Tick:      tick.currentTick,
-				ErrorMsg:  err.Error(),
-			},
-		)
-		if err != nil {
-			t.logger.Errorf(
-				"Failed to save execution info for task '%s': %v",
-				t.name, err,
-			)
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		span.SetAttribute("task.status", string(store.ExecutionStatusPanic))
+		span.RecordError(fmt.Errorf("panic: %v", panicErr.Value))
+
+		errorMsg := panicErr.Error()
+		if len(panicErr.Stack) > 0 {
+			errorMsg += "\n" + string(panicErr.Stack)
 		}
-		return
+
+		info := &store.ExecutionInfo{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Duration:  duration,
+			Status:    store.ExecutionStatusPanic,
+			Tick:      tick.currentTick,
+			Attempt:   attempt,
+			Manual:    tick.manual,
+			ErrorMsg:  errorMsg,
+			Logs:      capturedLogs(),
+
+			ExecutionID:       executionID,
+			ParentExecutionID: tick.parentExecutionID,
+		}
+		t.saveExecution(tick, info)
+		t.hooks.panicked(logger, &ctxTask, info, panicErr.Value)
+		return info, nil
 	}
 
-	t.logger.Infof("Task '%s' completed successfully", t.name)
+	if err != nil {
+		span.SetAttribute("task.status", string(store.ExecutionStatusError))
+		span.RecordError(err)
 
-	err = t.store.SaveExecution(
-		t.name,
-		&store.ExecutionInfo{
+		info := &store.ExecutionInfo{
 			StartTime: startTime,
 			EndTime:   endTime,
 			Duration:  duration,
-			Status:    store.ExecutionStatusSuccess,
+			Status:    store.ExecutionStatusError,
 			Tick:      tick.currentTick,
-		},
-	)
-	if err != nil {
-		t.logger.Errorf(
-			"Failed to save execution info for task '%s': %v",
-			t.name, err,
-		)
+			Attempt:   attempt,
+			Manual:    tick.manual,
+			ErrorMsg:  err.Error(),
+			Logs:      capturedLogs(),
+
+			ExecutionID:       executionID,
+			ParentExecutionID: tick.parentExecutionID,
+		}
+		t.saveExecution(tick, info)
+		t.hooks.failure(logger, &ctxTask, info, err)
+		return info, err
 	}
+
+	span.SetAttribute("task.status", string(store.ExecutionStatusSuccess))
+
+	info := &store.ExecutionInfo{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Duration:  duration,
+		Status:    store.ExecutionStatusSuccess,
+		Tick:      tick.currentTick,
+		Attempt:   attempt,
+		Manual:    tick.manual,
+		Logs:      capturedLogs(),
+
+		ExecutionID:       executionID,
+		ParentExecutionID: tick.parentExecutionID,
+	}
+	t.saveExecution(tick, info)
+	t.hooks.success(logger, &ctxTask, info)
+	return info, nil
+}
+
+// runJob calls the job through its middlewares. A panic that no middleware
+// recovered is still turned into a PanicError, without the stack trace
+// RecoveryMiddleware would add, so it never takes the worker down.
+func (t *Task) runJob(ctx *Context, logger Logger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("PANIC in Task '%s' job: %v", t.name, r)
+			err = &PanicError{Value: r}
+		}
+	}()
+	return t.wrappedJob()(ctx)
+}
+
+// newExecutionID returns a random id that ties together the log lines and
+// attempts of one execution.
+func newExecutionID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
 }
 
 func NewTask(name string, job Job, options ...taskOption) (*Task, error) {
@@ -145,12 +369,18 @@ func NewTask(name string, job Job, options ...taskOption) (*Task, error) {
 		job:     job,
 		name:    name,
 		jobName: jobName,
+		metrics: nopMetricsSink{},
+		tracer:  nopTracer{},
 	}
 
 	for _, opt := range options {
 		opt(task)
 	}
 
+	// Tasks executed outside of an engine keep the default middlewares.
+	task.middleware.engine = DefaultMiddleware()
+	task.middleware.compose(job)
+
 	return task, nil
 }
 
@@ -161,3 +391,78 @@ func WithTimeout(timeout time.Duration) taskOption {
 		t.timeout = timeout
 	}
 }
+
+// WithRetry re-executes the job when it returns an error, as described by
+// policy. Every attempt is saved as its own execution.
+func WithRetry(policy RetryPolicy) taskOption {
+	return func(t *Task) {
+		t.retry = &policy
+	}
+}
+
+// WithMaxConcurrency limits how many executions of the task run at once
+// under WorkerPolicyParallel. Excess ticks are handled as configured by
+// WithConcurrencyOverflow, waiting in the dispatcher by default.
+func WithMaxConcurrency(n int) taskOption {
+	return func(t *Task) {
+		t.maxConcurrency = n
+	}
+}
+
+func WithConcurrencyOverflow(overflow concurrencyOverflow) taskOption {
+	return func(t *Task) {
+		t.concurrencyOverflow = overflow
+	}
+}
+
+// WithExactlyOnce claims every scheduled tick in the store before executing
+// it, so that engines sharing the store execute each tick at most once.
+// Manual runs are not claimed.
+func WithExactlyOnce() taskOption {
+	return func(t *Task) {
+		t.exactlyOnce = true
+	}
+}
+
+// WithLogCapture saves what the job logs through its Context with every
+// execution, keeping at most limit bytes, or DefaultLogCaptureLimit when
+// limit is not positive.
+func WithLogCapture(limit int) taskOption {
+	return func(t *Task) {
+		if limit <= 0 {
+			limit = DefaultLogCaptureLimit
+		}
+		t.logCaptureLimit = limit
+	}
+}
+
+// WithOnStart calls hook before every execution attempt of the job.
+func WithOnStart(hook StartHook) taskOption {
+	return func(t *Task) {
+		t.hooks.onStart = append(t.hooks.onStart, hook)
+	}
+}
+
+// WithOnSuccess calls hook with the saved execution after every attempt that
+// succeeded.
+func WithOnSuccess(hook SuccessHook) taskOption {
+	return func(t *Task) {
+		t.hooks.onSuccess = append(t.hooks.onSuccess, hook)
+	}
+}
+
+// WithOnFailure calls hook with the saved execution and the job error after
+// every attempt that failed, including attempts that are retried.
+func WithOnFailure(hook FailureHook) taskOption {
+	return func(t *Task) {
+		t.hooks.onFailure = append(t.hooks.onFailure, hook)
+	}
+}
+
+// WithOnPanic calls hook with the saved execution and the recovered value
+// after every attempt that panicked.
+func WithOnPanic(hook PanicHook) taskOption {
+	return func(t *Task) {
+		t.hooks.onPanic = append(t.hooks.onPanic, hook)
+	}
+}
@@ -0,0 +1,173 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func newTestTask(t *testing.T, job Job, options ...taskOption) (*Task, store.Store) {
+	t.Helper()
+
+	task, err := NewTask("task", job, options...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ms := memory.NewStore()
+	if err := ms.SaveTask(task.name, &store.TaskSettings{Job: task.jobName}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	task.setLogger(func(string) Logger { return &mockLogger{} })
+	task.setStore(ms)
+	return task, ms
+}
+
+func listExecutions(t *testing.T, s store.Store) []*store.Execution {
+	t.Helper()
+
+	executions, err := s.ListExecutions("task", &store.ExecutionFilter{Order: store.ExecutionOrderOldestFirst})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return executions
+}
+
+func TestTaskExecuteStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		job    Job
+		status store.ExecutionStatus
+	}{
+		{"success", func(ctx *Context) error { return nil }, store.ExecutionStatusSuccess},
+		{"error", func(ctx *Context) error { return errors.New("boom") }, store.ExecutionStatusError},
+		{"panic", func(ctx *Context) error { panic("boom") }, store.ExecutionStatusPanic},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task, s := newTestTask(t, tc.job)
+			tick := &Tick{currentTick: time.Now()}
+
+			task.Execute(context.Background(), tick)
+
+			executions := listExecutions(t, s)
+			if len(executions) != 1 {
+				t.Fatalf("expected 1 execution, got %d", len(executions))
+			}
+			if got := executions[0].Status; got != tc.status {
+				t.Errorf("expected status %s, got %s", tc.status, got)
+			}
+			if got := executions[0].Attempt; got != 1 {
+				t.Errorf("expected attempt 1, got %d", got)
+			}
+		})
+	}
+}
+
+func TestTaskExecuteRetries(t *testing.T) {
+	var attempts []int
+	job := func(ctx *Context) error {
+		attempts = append(attempts, ctx.Attempt())
+		if ctx.Attempt() < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}
+
+	task, s := newTestTask(t, job, WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     FixedBackoff(time.Millisecond),
+	}))
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %v", attempts)
+	}
+
+	executions := listExecutions(t, s)
+	want := []store.ExecutionStatus{
+		store.ExecutionStatusError,
+		store.ExecutionStatusError,
+		store.ExecutionStatusSuccess,
+	}
+	if len(executions) != len(want) {
+		t.Fatalf("expected %d executions, got %d", len(want), len(executions))
+	}
+	for i, execution := range executions {
+		if execution.Status != want[i] {
+			t.Errorf("expected status %s for attempt %d, got %s", want[i], i+1, execution.Status)
+		}
+		if execution.Attempt != i+1 {
+			t.Errorf("expected attempt %d, got %d", i+1, execution.Attempt)
+		}
+	}
+}
+
+func TestTaskExecuteDoesNotRetryPanics(t *testing.T) {
+	calls := 0
+	job := func(ctx *Context) error { calls++; panic("boom") }
+
+	task, _ := newTestTask(t, job, WithRetry(RetryPolicy{MaxAttempts: 3}))
+	task.Execute(context.Background(), &Tick{currentTick: time.Now()})
+
+	if calls != 1 {
+		t.Errorf("expected panicking job to run once, got %d", calls)
+	}
+}
+
+func TestTaskExecuteRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	job := func(*Context) error { calls++; cancel(); return errors.New("boom") }
+
+	task, _ := newTestTask(t, job, WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     FixedBackoff(time.Hour),
+	}))
+	task.Execute(ctx, &Tick{currentTick: time.Now()})
+
+	if calls != 1 {
+		t.Errorf("expected retries to stop once the context is done, got %d calls", calls)
+	}
+}
+
+func TestTaskExecuteExactlyOnce(t *testing.T) {
+	var runs int
+	job := func(ctx *Context) error { runs++; return nil }
+
+	first, s := newTestTask(t, job, WithExactlyOnce())
+	first.setNodeID("node-a")
+
+	second, err := NewTask("task", job, WithExactlyOnce())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second.setLogger(func(string) Logger { return &mockLogger{} })
+	second.setStore(s)
+	second.setNodeID("node-b")
+
+	tick := &Tick{currentTick: time.Now()}
+	first.Execute(context.Background(), tick)
+	second.Execute(context.Background(), tick)
+
+	if runs != 1 {
+		t.Errorf("expected the tick to run once, got %d runs", runs)
+	}
+
+	manual := &Tick{currentTick: time.Now(), manual: true}
+	first.Execute(context.Background(), manual)
+	second.Execute(context.Background(), manual)
+
+	if runs != 3 {
+		t.Errorf("expected manual runs not to be claimed, got %d runs", runs)
+	}
+	if got := len(listExecutions(t, s)); got != 3 {
+		t.Errorf("expected 3 executions, got %d", got)
+	}
+}
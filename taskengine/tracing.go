@@ -0,0 +1,32 @@
+package taskengine
+
+import "context"
+
+// Tracer starts a span around every execution attempt. It covers the small
+// part of OpenTelemetry the engine needs, so production code can adapt an
+// OTel tracer to it and tests can record spans in memory.
+type Tracer interface {
+	// Start returns a span and a context carrying it, which becomes the
+	// parent context of the job.
+	Start(ctx context.Context, name string, attributes map[string]any) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ map[string]any) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(string, any) {}
+
+func (nopSpan) RecordError(error) {}
+
+func (nopSpan) End() {}
@@ -0,0 +1,104 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type spanKey struct{}
+
+type mockSpan struct {
+	name       string
+	attributes map[string]any
+	errors     []error
+	ended      bool
+}
+
+func (s *mockSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+
+func (s *mockSpan) RecordError(err error) { s.errors = append(s.errors, err) }
+
+func (s *mockSpan) End() { s.ended = true }
+
+type mockTracer struct {
+	mu    sync.Mutex
+	spans []*mockSpan
+}
+
+func (m *mockTracer) Start(
+	ctx context.Context, name string, attributes map[string]any,
+) (context.Context, Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	span := &mockSpan{name: name, attributes: attributes}
+	m.spans = append(m.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTaskExecuteTracing(t *testing.T) {
+	calls := 0
+	job := func(ctx *Context) error {
+		if ctx.Value(spanKey{}) != ctx.Span() {
+			t.Error("expected the span to be carried by the job context")
+		}
+		calls++
+		switch calls {
+		case 1:
+			return errors.New("boom")
+		case 2:
+			return nil
+		default:
+			panic("boom")
+		}
+	}
+
+	tracer := &mockTracer{}
+	task, _ := newTestTask(t, job, WithRetry(RetryPolicy{MaxAttempts: 2}))
+	task.setTracer(tracer)
+	task.setPolicy(WorkerPolicyParallel)
+
+	tick := &Tick{}
+	task.Execute(context.Background(), tick)
+	task.Execute(context.Background(), tick)
+
+	tests := []struct {
+		attempt int
+		status  string
+		errors  int
+	}{
+		{1, "error", 1},
+		{2, "success", 0},
+		{1, "panic", 1},
+	}
+
+	if len(tracer.spans) != len(tests) {
+		t.Fatalf("expected %d spans, got %d", len(tests), len(tracer.spans))
+	}
+	for i, tc := range tests {
+		span := tracer.spans[i]
+		if !span.ended {
+			t.Errorf("span %d: expected span to be ended", i)
+		}
+		if span.name != "taskengine.execute" {
+			t.Errorf("span %d: expected name taskengine.execute, got %s", i, span.name)
+		}
+		if got := span.attributes["task.attempt"]; got != tc.attempt {
+			t.Errorf("span %d: expected attempt %d, got %v", i, tc.attempt, got)
+		}
+		if got := span.attributes["task.status"]; got != tc.status {
+			t.Errorf("span %d: expected status %s, got %v", i, tc.status, got)
+		}
+		if got := span.attributes["task.policy"]; got != "parallel" {
+			t.Errorf("span %d: expected policy parallel, got %v", i, got)
+		}
+		if got := span.attributes["task.name"]; got != "task" {
+			t.Errorf("span %d: expected task name task, got %v", i, got)
+		}
+		if len(span.errors) != tc.errors {
+			t.Errorf("span %d: expected %d recorded errors, got %d", i, tc.errors, len(span.errors))
+		}
+	}
+}
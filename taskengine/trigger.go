@@ -3,8 +3,11 @@ package taskengine
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/MAD-py/go-taskengine/taskengine/store"
 	"github.com/adhocore/gronx"
 )
 
@@ -46,13 +49,24 @@ func NewIntervalTrigger(interval time.Duration, runOnStart bool) (Trigger, error
 
 type cronTrigger struct {
 	expr       string
+	location   *time.Location
 	runOnStart bool
 }
 
 func (t *cronTrigger) String() string {
-	return fmt.Sprintf("Cron(expr=%s, runOnStart=%v)", t.expr, t.runOnStart)
+	if t.location == nil {
+		return fmt.Sprintf("Cron(expr=%s, runOnStart=%v)", t.expr, t.runOnStart)
+	}
+	return fmt.Sprintf(
+		"Cron(expr=%s, location=%s, runOnStart=%v)",
+		t.expr, t.location, t.runOnStart,
+	)
 }
 
+// Next evaluates the expression against the wall clock of the trigger
+// location, or the local time zone when none was given. Wall clock times
+// skipped by a DST transition fire once, when the gap ends; wall clock times
+// repeated by a transition fire only on their first occurrence.
 func (t *cronTrigger) Next(lastRun time.Time) (time.Time, error) {
 	if lastRun.IsZero() {
 		if t.runOnStart {
@@ -61,16 +75,391 @@ func (t *cronTrigger) Next(lastRun time.Time) (time.Time, error) {
 		lastRun = time.Now()
 	}
 
-	next, err := gronx.NextTickAfter(t.expr, lastRun, false)
-	if err != nil {
-		return time.Time{}, err
+	location := t.location
+	if location == nil {
+		location = time.Local
+	}
+
+	from := wallClock(lastRun.In(location))
+	for {
+		next, err := gronx.NextTickAfter(t.expr, from, false)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		// A repeated wall clock time resolves to its first occurrence,
+		// which may not be after lastRun when lastRun is the second one.
+		if resolved := inLocation(next, location); resolved.After(lastRun) {
+			return resolved, nil
+		}
+		from = next
+	}
+}
+
+// wallClock returns the wall clock of t as a UTC time, a zone in which every
+// wall clock time exists exactly once.
+func wallClock(t time.Time) time.Time {
+	return time.Date(
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		time.UTC,
+	)
+}
+
+// inLocation maps a wall clock time returned by wallClock back to location.
+// Times skipped by a DST transition resolve to the end of the gap, and times
+// repeated by a transition resolve to their first occurrence.
+func inLocation(wall time.Time, location *time.Location) time.Time {
+	t := time.Date(
+		wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(),
+		location,
+	)
+
+	start, end := t.ZoneBounds()
+	if start.IsZero() {
+		return t
 	}
-	return next, nil
+
+	// A skipped time resolves to either side of the gap depending on the
+	// zone; the gap ends at the transition next to it.
+	if resolved := wallClock(t); !resolved.Equal(wall) {
+		if resolved.Before(wall) {
+			return end
+		}
+		return start
+	}
+
+	_, offset := t.Zone()
+	_, previousOffset := start.Add(-time.Nanosecond).Zone()
+	if shift := time.Duration(previousOffset-offset) * time.Second; shift > 0 {
+		if earlier := t.Add(-shift); earlier.Before(start) {
+			return earlier
+		}
+	}
+	return t
+}
+
+// parseCronTimezone splits an optional "CRON_TZ=<zone>" or "TZ=<zone>"
+// prefix from a cron expression.
+func parseCronTimezone(expr string) (*time.Location, string, error) {
+	expr = strings.TrimSpace(expr)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if !strings.HasPrefix(expr, prefix) {
+			continue
+		}
+
+		zone, rest, _ := strings.Cut(strings.TrimPrefix(expr, prefix), " ")
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cron time zone: %w", err)
+		}
+		return location, strings.TrimSpace(rest), nil
+	}
+	return nil, expr, nil
 }
 
+// NewCronTrigger accepts an optional "CRON_TZ=<zone>" prefix, e.g.
+// "CRON_TZ=Europe/Madrid 0 9 * * *", to evaluate the expression in that zone.
 func NewCronTrigger(expr string, runOnStart bool) (Trigger, error) {
+	location, expr, err := parseCronTimezone(expr)
+	if err != nil {
+		return nil, err
+	}
+
 	if !gronx.IsValid(expr) {
 		return nil, errors.New("invalid cron expression")
 	}
-	return &cronTrigger{expr: expr, runOnStart: runOnStart}, nil
+	return &cronTrigger{expr: expr, location: location, runOnStart: runOnStart}, nil
+}
+
+func NewCronTriggerIn(expr string, location *time.Location, runOnStart bool) (Trigger, error) {
+	if location == nil {
+		return nil, errors.New("location must be non-nil")
+	}
+
+	if !gronx.IsValid(expr) {
+		return nil, errors.New("invalid cron expression")
+	}
+	return &cronTrigger{expr: expr, location: location, runOnStart: runOnStart}, nil
+}
+
+type datesTrigger struct {
+	dates []time.Time
+}
+
+func (t *datesTrigger) String() string {
+	dates := make([]string, len(t.dates))
+	for i, date := range t.dates {
+		dates[i] = date.Format(time.RFC3339)
+	}
+	if len(dates) == 1 {
+		return fmt.Sprintf("At(time=%s)", dates[0])
+	}
+	return fmt.Sprintf("Dates(times=[%s])", strings.Join(dates, " "))
+}
+
+func (t *datesTrigger) Next(lastRun time.Time) (time.Time, error) {
+	for _, date := range t.dates {
+		if lastRun.IsZero() || date.After(lastRun) {
+			return date, nil
+		}
+	}
+	return time.Time{}, ErrorNoMoreTicks
+}
+
+// NewAtTrigger fires once at the given time.
+func NewAtTrigger(at time.Time) (Trigger, error) {
+	if at.IsZero() {
+		return nil, errors.New("time must be non-zero")
+	}
+	return &datesTrigger{dates: []time.Time{at}}, nil
+}
+
+// NewDatesTrigger fires once at each of the given times, in chronological
+// order whatever the order of dates.
+func NewDatesTrigger(dates []time.Time) (Trigger, error) {
+	if len(dates) == 0 {
+		return nil, errors.New("dates must be non-empty")
+	}
+
+	sorted := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		if date.IsZero() {
+			return nil, errors.New("dates must be non-zero")
+		}
+		sorted = append(sorted, date)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	unique := sorted[:1]
+	for _, date := range sorted[1:] {
+		if !date.Equal(unique[len(unique)-1]) {
+			unique = append(unique, date)
+		}
+	}
+	return &datesTrigger{dates: unique}, nil
+}
+
+// eventTrigger is a trigger fired by engine events instead of the clock. The
+// scheduler dispatches the tick returned for every event matching filter.
+type eventTrigger interface {
+	Trigger
+	filter() EventFilter
+	tick(event Event) (*Tick, bool)
+}
+
+type afterTaskTrigger struct {
+	upstream string
+	statuses []store.ExecutionStatus
+}
+
+func (t *afterTaskTrigger) String() string {
+	return fmt.Sprintf("AfterTask(upstream=%s, statuses=%v)", t.upstream, t.statuses)
+}
+
+// Next has no tick to offer: the trigger only fires when the upstream task
+// completes.
+func (t *afterTaskTrigger) Next(lastRun time.Time) (time.Time, error) {
+	return time.Time{}, errors.New("after-task triggers are fired by their upstream task")
+}
+
+func (t *afterTaskTrigger) filter() EventFilter {
+	return EventFilter{
+		Types: []EventType{EventExecutionCompleted, EventTickDropped},
+		Tasks: []string{t.upstream},
+	}
+}
+
+func (t *afterTaskTrigger) tick(event Event) (*Tick, bool) {
+	if event.Execution == nil || !contains(t.statuses, event.Execution.Status) {
+		return nil, false
+	}
+	return &Tick{
+		lastTick:    event.LastTick,
+		currentTick: event.Tick,
+		manual:      event.Manual,
+	}, true
+}
+
+// NewAfterTaskTrigger fires whenever an execution of the upstream task
+// completes with one of statuses, successful ones by default. The tick of the
+// upstream execution is passed through, so the downstream job sees the same
+// LastTick and CurrentTick. Upstream retries only fire it once, with the
+// status of the last attempt.
+func NewAfterTaskTrigger(upstream string, statuses ...store.ExecutionStatus) (Trigger, error) {
+	if upstream == "" {
+		return nil, errors.New("upstream task name must be non-empty")
+	}
+	if len(statuses) == 0 {
+		statuses = []store.ExecutionStatus{store.ExecutionStatusSuccess}
+	}
+	return &afterTaskTrigger{upstream: upstream, statuses: statuses}, nil
+}
+
+// statefulTrigger is a trigger whose ticks depend on state kept in the store
+// under the name of its task.
+type statefulTrigger interface {
+	Trigger
+	nextFor(lastRun time.Time, task string, s store.Store) (time.Time, error)
+	// countsOccurrences reports whether the ticks that run must be counted
+	// with store.IncrementOccurrences.
+	countsOccurrences() bool
+}
+
+// triggerNext returns the tick following lastRun for the task, letting
+// stateful triggers read their state from the store.
+func triggerNext(trigger Trigger, lastRun time.Time, task string, s store.Store) (time.Time, error) {
+	if t, ok := trigger.(statefulTrigger); ok && s != nil {
+		return t.nextFor(lastRun, task, s)
+	}
+	return trigger.Next(lastRun)
+}
+
+func countsOccurrences(trigger Trigger) bool {
+	t, ok := trigger.(statefulTrigger)
+	return ok && t.countsOccurrences()
+}
+
+func formatBound(t time.Time) string {
+	if t.IsZero() {
+		return "none"
+	}
+	return t.Format(time.RFC3339)
+}
+
+type betweenTrigger struct {
+	trigger Trigger
+	start   time.Time
+	end     time.Time
+}
+
+func (t *betweenTrigger) String() string {
+	return fmt.Sprintf(
+		"Between(trigger=%s, start=%s, end=%s)",
+		t.trigger, formatBound(t.start), formatBound(t.end),
+	)
+}
+
+func (t *betweenTrigger) Next(lastRun time.Time) (time.Time, error) {
+	return t.window(lastRun, t.trigger.Next)
+}
+
+func (t *betweenTrigger) nextFor(lastRun time.Time, task string, s store.Store) (time.Time, error) {
+	return t.window(lastRun, func(lastRun time.Time) (time.Time, error) {
+		return triggerNext(t.trigger, lastRun, task, s)
+	})
+}
+
+func (t *betweenTrigger) countsOccurrences() bool { return countsOccurrences(t.trigger) }
+
+// window skips the ticks before start and ends the schedule at end.
+func (t *betweenTrigger) window(
+	lastRun time.Time, next func(time.Time) (time.Time, error),
+) (time.Time, error) {
+	tick, err := next(lastRun)
+	if err == nil && tick.Before(t.start) {
+		tick, err = t.seek(tick, next)
+	}
+	for err == nil && tick.Before(t.start) {
+		previous := tick
+		if tick, err = next(previous); err == nil && !tick.After(previous) {
+			return time.Time{}, errors.New("trigger does not advance")
+		}
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !t.end.IsZero() && !tick.Before(t.end) {
+		return time.Time{}, ErrorNoMoreTicks
+	}
+	return tick, nil
+}
+
+// seek moves tick, which is before start, close to start without walking
+// every tick in between. It jumps by whole steps of the gap between tick and
+// the tick following it, which keeps interval triggers in phase, and stops
+// short of start so the caller walks the last ticks.
+func (t *betweenTrigger) seek(
+	tick time.Time, next func(time.Time) (time.Time, error),
+) (time.Time, error) {
+	following, err := next(tick)
+	if err != nil || !following.After(tick) {
+		return tick, nil
+	}
+
+	step := following.Sub(tick)
+	if skip := t.start.Sub(tick) / step; skip > 1 {
+		return next(tick.Add((skip - 1) * step))
+	}
+	return tick, nil
+}
+
+// Between restricts trigger to the ticks from start, included, to end,
+// excluded, after which the task completes. A zero start or end leaves that
+// side of the window open. Event-driven triggers cannot be wrapped.
+func Between(trigger Trigger, start, end time.Time) (Trigger, error) {
+	if err := checkDecorated(trigger); err != nil {
+		return nil, err
+	}
+	if start.IsZero() && end.IsZero() {
+		return nil, errors.New("start or end must be non-zero")
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end must be after start")
+	}
+	return &betweenTrigger{trigger: trigger, start: start, end: end}, nil
+}
+
+type limitTrigger struct {
+	trigger Trigger
+	limit   int
+}
+
+func (t *limitTrigger) String() string {
+	return fmt.Sprintf("Limit(trigger=%s, n=%d)", t.trigger, t.limit)
+}
+
+// Next cannot tell how many ticks ran without the task, so it
+// leaves the limit to nextFor.
+func (t *limitTrigger) Next(lastRun time.Time) (time.Time, error) {
+	return t.trigger.Next(lastRun)
+}
+
+func (t *limitTrigger) nextFor(lastRun time.Time, task string, s store.Store) (time.Time, error) {
+	occurrences, err := s.GetOccurrences(task)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if occurrences >= t.limit {
+		return time.Time{}, ErrorNoMoreTicks
+	}
+	return triggerNext(t.trigger, lastRun, task, s)
+}
+
+func (t *limitTrigger) countsOccurrences() bool { return true }
+
+// Limit stops trigger after n of its ticks ran, after which the task
+// completes. Ticks that were skipped or dropped do not count. The count is
+// kept in the store, so restarts do not reset it. Event-driven triggers
+// cannot be wrapped.
+func Limit(trigger Trigger, n int) (Trigger, error) {
+	if err := checkDecorated(trigger); err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	return &limitTrigger{trigger: trigger, limit: n}, nil
+}
+
+func checkDecorated(trigger Trigger) error {
+	if trigger == nil {
+		return errors.New("trigger must be non-nil")
+	}
+	if _, ok := trigger.(eventTrigger); ok {
+		return errors.New("event-driven triggers cannot be decorated")
+	}
+	return nil
 }
@@ -1,9 +1,13 @@
 package taskengine
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
 )
 
 func TestIntervalTriggerString(t *testing.T) {
@@ -386,4 +390,511 @@ func TestCronTriggerNextError(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for invalid cron expression in NextTickAfter, got nil")
 	}
-}
\ No newline at end of file
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return location
+}
+
+func TestCronTriggerStringWithLocation(t *testing.T) {
+	location := mustLoadLocation(t, "Europe/Berlin")
+	trigger := &cronTrigger{expr: "0 9 * * *", location: location}
+
+	want := "Cron(expr=0 9 * * *, location=Europe/Berlin, runOnStart=false)"
+	if got := trigger.String(); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestNewCronTriggerTimezonePrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "CRON_TZ prefix",
+			expr:     "CRON_TZ=America/New_York 0 9 * * *",
+			expected: "Cron(expr=0 9 * * *, location=America/New_York, runOnStart=false)",
+		},
+		{
+			name:     "TZ prefix",
+			expr:     "TZ=UTC */5 * * * *",
+			expected: "Cron(expr=*/5 * * * *, location=UTC, runOnStart=false)",
+		},
+		{
+			name:     "no prefix",
+			expr:     "0 9 * * *",
+			expected: "Cron(expr=0 9 * * *, runOnStart=false)",
+		},
+		{
+			name:        "unknown zone",
+			expr:        "CRON_TZ=Mars/Olympus 0 9 * * *",
+			expectError: true,
+		},
+		{
+			name:        "prefix without expression",
+			expr:        "CRON_TZ=UTC",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trigger, err := NewCronTrigger(tc.expr, false)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error for %s, got nil", tc.expr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error for %s, got %v", tc.expr, err)
+			}
+			if got := trigger.String(); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewCronTriggerIn(t *testing.T) {
+	if _, err := NewCronTriggerIn("0 9 * * *", nil, false); err == nil {
+		t.Error("expected error for nil location, got nil")
+	}
+
+	if _, err := NewCronTriggerIn("invalid", time.UTC, false); err == nil {
+		t.Error("expected error for invalid expression, got nil")
+	}
+
+	trigger, err := NewCronTriggerIn("0 9 * * *", time.UTC, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := "Cron(expr=0 9 * * *, location=UTC, runOnStart=true)"; trigger.String() != want {
+		t.Errorf("expected %s, got %s", want, trigger.String())
+	}
+}
+
+func TestCronTriggerNextInLocation(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		lastRun  time.Time
+		expected time.Time
+	}{
+		{
+			name:     "evaluated in trigger location regardless of lastRun zone",
+			expr:     "0 9 * * *",
+			location: newYork,
+			lastRun:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 6, 1, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:     "daily job in skipped hour fires when the gap ends",
+			expr:     "30 2 * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 3, 30, 2, 30, 0, 0, berlin),
+			expected: time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+		},
+		{
+			name:     "daily job resumes normally after the gap",
+			expr:     "30 2 * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+			expected: time.Date(2024, 4, 1, 2, 30, 0, 0, berlin),
+		},
+		{
+			name:     "hourly job across the gap",
+			expr:     "0 * * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 3, 31, 1, 0, 0, 0, berlin),
+			expected: time.Date(2024, 3, 31, 3, 0, 0, 0, berlin),
+		},
+		{
+			name:     "daily job in repeated hour fires on first occurrence",
+			expr:     "30 2 * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 10, 26, 2, 30, 0, 0, berlin),
+			expected: time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily job does not fire again in repeated hour",
+			expr:     "30 2 * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC),
+			expected: time.Date(2024, 10, 28, 2, 30, 0, 0, berlin),
+		},
+		{
+			name:     "hourly job skips second pass of repeated hour",
+			expr:     "0 * * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 10, 27, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily job in skipped hour west of UTC fires when the gap ends",
+			expr:     "30 2 * * *",
+			location: newYork,
+			lastRun:  time.Date(2026, 3, 7, 12, 0, 0, 0, newYork),
+			expected: time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			name:     "hourly job across the gap west of UTC",
+			expr:     "0 * * * *",
+			location: newYork,
+			lastRun:  time.Date(2026, 3, 8, 1, 0, 0, 0, newYork),
+			expected: time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			name:     "daily job in repeated hour west of UTC fires on first occurrence",
+			expr:     "30 1 * * *",
+			location: newYork,
+			lastRun:  time.Date(2026, 10, 31, 1, 30, 0, 0, newYork),
+			expected: time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily job does not fire again in repeated hour west of UTC",
+			expr:     "30 1 * * *",
+			location: newYork,
+			lastRun:  time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 11, 2, 1, 30, 0, 0, newYork),
+		},
+		{
+			name:     "lastRun in second pass of repeated hour",
+			expr:     "*/15 * * * *",
+			location: berlin,
+			lastRun:  time.Date(2024, 10, 27, 1, 10, 0, 0, time.UTC),
+			expected: time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trigger := &cronTrigger{expr: tc.expr, location: tc.location}
+
+			next, err := trigger.Next(tc.lastRun)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !next.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestNewAfterTaskTrigger(t *testing.T) {
+	if _, err := NewAfterTaskTrigger(""); err == nil {
+		t.Error("expected an error for an empty upstream")
+	}
+
+	tests := []struct {
+		name     string
+		statuses []store.ExecutionStatus
+		expected string
+	}{
+		{"default", nil, "AfterTask(upstream=extract, statuses=[success])"},
+		{"failures", []store.ExecutionStatus{store.ExecutionStatusError, store.ExecutionStatusPanic}, "AfterTask(upstream=extract, statuses=[error panic])"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trigger, err := NewAfterTaskTrigger("extract", tc.statuses...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := trigger.String(); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+			if _, err := trigger.Next(time.Now()); err == nil {
+				t.Error("expected Next to return an error")
+			}
+		})
+	}
+}
+
+func TestAfterTaskTriggerTick(t *testing.T) {
+	trigger, _ := NewAfterTaskTrigger("extract", store.ExecutionStatusSuccess)
+	lastTick := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	currentTick := lastTick.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		status store.ExecutionStatus
+		fires  bool
+	}{
+		{"matching status", store.ExecutionStatusSuccess, true},
+		{"other status", store.ExecutionStatusError, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tick, fires := trigger.(eventTrigger).tick(Event{
+				Type:      EventExecutionCompleted,
+				Task:      "extract",
+				Tick:      currentTick,
+				LastTick:  lastTick,
+				Execution: &store.ExecutionInfo{Status: tc.status},
+			})
+			if fires != tc.fires {
+				t.Fatalf("expected fires %v, got %v", tc.fires, fires)
+			}
+			if fires && (!tick.lastTick.Equal(lastTick) || !tick.currentTick.Equal(currentTick)) {
+				t.Errorf("expected upstream ticks %v and %v, got %v and %v", lastTick, currentTick, tick.lastTick, tick.currentTick)
+			}
+		})
+	}
+}
+
+func TestNewAtTrigger(t *testing.T) {
+	if _, err := NewAtTrigger(time.Time{}); err == nil {
+		t.Error("expected an error for a zero time")
+	}
+
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	trigger, err := NewAtTrigger(at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := "At(time=2024-01-01T09:00:00Z)"; trigger.String() != expected {
+		t.Errorf("expected %s, got %s", expected, trigger.String())
+	}
+
+	tests := []struct {
+		name     string
+		lastRun  time.Time
+		expected time.Time
+		err      error
+	}{
+		{"never run", time.Time{}, at, nil},
+		{"run before", at.Add(-time.Hour), at, nil},
+		{"already run", at, time.Time{}, ErrorNoMoreTicks},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := trigger.Next(tc.lastRun)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if !next.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestNewDatesTrigger(t *testing.T) {
+	first := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	invalid := [][]time.Time{nil, {first, {}}}
+	for _, dates := range invalid {
+		if _, err := NewDatesTrigger(dates); err == nil {
+			t.Errorf("expected an error for dates %v", dates)
+		}
+	}
+
+	trigger, err := NewDatesTrigger([]time.Time{second, first, second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Dates(times=[2024-01-01T09:00:00Z 2024-01-02T09:00:00Z])"
+	if trigger.String() != expected {
+		t.Errorf("expected %s, got %s", expected, trigger.String())
+	}
+
+	var lastRun time.Time
+	for _, date := range []time.Time{first, second} {
+		next, err := trigger.Next(lastRun)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !next.Equal(date) {
+			t.Fatalf("expected %v, got %v", date, next)
+		}
+		lastRun = next
+	}
+
+	if _, err := trigger.Next(lastRun); !errors.Is(err, ErrorNoMoreTicks) {
+		t.Errorf("expected ErrorNoMoreTicks, got %v", err)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval, _ := NewIntervalTrigger(time.Hour, false)
+	after, _ := NewAfterTaskTrigger("upstream")
+
+	invalid := []struct {
+		name       string
+		trigger    Trigger
+		start, end time.Time
+	}{
+		{"nil trigger", nil, base, time.Time{}},
+		{"event trigger", after, base, time.Time{}},
+		{"open window", interval, time.Time{}, time.Time{}},
+		{"end before start", interval, base, base.Add(-time.Hour)},
+	}
+	for _, tc := range invalid {
+		if _, err := Between(tc.trigger, tc.start, tc.end); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+
+	trigger, err := Between(interval, base.Add(150*time.Minute), base.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Between(trigger=Interval(interval=1h0m0s, runOnStart=false), start=2024-01-01T02:30:00Z, end=2024-01-01T05:00:00Z)"
+	if trigger.String() != expected {
+		t.Errorf("expected %s, got %s", expected, trigger.String())
+	}
+
+	open, _ := Between(interval, time.Time{}, base)
+	if expected := "Between(trigger=Interval(interval=1h0m0s, runOnStart=false), start=none, end=2024-01-01T00:00:00Z)"; open.String() != expected {
+		t.Errorf("expected %s, got %s", expected, open.String())
+	}
+
+	tests := []struct {
+		name     string
+		lastRun  time.Time
+		expected time.Time
+		err      error
+	}{
+		{"before start", base, base.Add(3 * time.Hour), nil},
+		{"within window", base.Add(3 * time.Hour), base.Add(4 * time.Hour), nil},
+		{"at end", base.Add(4 * time.Hour), time.Time{}, ErrorNoMoreTicks},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := trigger.Next(tc.lastRun)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if !next.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
+type countingTrigger struct {
+	Trigger
+	calls int
+}
+
+func (t *countingTrigger) Next(lastRun time.Time) (time.Time, error) {
+	t.calls++
+	return t.Trigger.Next(lastRun)
+}
+
+func TestBetweenSeeksDistantStart(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval, _ := NewIntervalTrigger(time.Second, false)
+	counting := &countingTrigger{Trigger: interval}
+
+	trigger, err := Between(counting, base.Add(365*24*time.Hour+500*time.Millisecond), time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	next, err := trigger.Next(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := base.Add(365*24*time.Hour + time.Second); !next.Equal(expected) {
+		t.Errorf("expected the first tick in phase with the last run %v, got %v", expected, next)
+	}
+	if counting.calls > 5 {
+		t.Errorf("expected the window to be reached in a few steps, got %d", counting.calls)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	interval, _ := NewIntervalTrigger(time.Hour, false)
+	after, _ := NewAfterTaskTrigger("upstream")
+
+	for _, n := range []int{0, -1} {
+		if _, err := Limit(interval, n); err == nil {
+			t.Errorf("expected an error for limit %d", n)
+		}
+	}
+	if _, err := Limit(after, 1); err == nil {
+		t.Error("expected an error for an event trigger")
+	}
+
+	trigger, err := Limit(interval, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Limit(trigger=Interval(interval=1h0m0s, runOnStart=false), n=2)"
+	if trigger.String() != expected {
+		t.Errorf("expected %s, got %s", expected, trigger.String())
+	}
+
+	ms := memory.NewStore()
+	if err := ms.SaveTask("task", &store.TaskSettings{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lastRun := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for occurrences := 0; occurrences < 2; occurrences++ {
+		next, err := triggerNext(trigger, lastRun, "task", ms)
+		if err != nil {
+			t.Fatalf("unexpected error after %d occurrences: %v", occurrences, err)
+		}
+		if !next.Equal(lastRun.Add(time.Hour)) {
+			t.Errorf("expected %v, got %v", lastRun.Add(time.Hour), next)
+		}
+		ms.IncrementOccurrences("task")
+		lastRun = next
+	}
+
+	if _, err := triggerNext(trigger, lastRun, "task", ms); !errors.Is(err, ErrorNoMoreTicks) {
+		t.Errorf("expected ErrorNoMoreTicks, got %v", err)
+	}
+}
+
+func TestCountsOccurrences(t *testing.T) {
+	interval, _ := NewIntervalTrigger(time.Hour, false)
+	limit, _ := Limit(interval, 1)
+	between, _ := Between(interval, time.Now(), time.Time{})
+	limitBetween, _ := Between(limit, time.Now(), time.Time{})
+
+	tests := []struct {
+		name     string
+		trigger  Trigger
+		expected bool
+	}{
+		{"interval", interval, false},
+		{"limit", limit, true},
+		{"between", between, false},
+		{"between limit", limitBetween, true},
+	}
+
+	for _, tc := range tests {
+		if got := countsOccurrences(tc.trigger); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
 )
 
 type workerPolicy int
@@ -34,6 +36,38 @@ const (
 	workerRunning
 )
 
+func (s workerState) String() string {
+	switch s {
+	case workerIdle:
+		return "idle"
+	case workerRunning:
+		return "running"
+	default:
+		return "unknown"
+	}
+}
+
+type concurrencyOverflow int
+
+const (
+	// ConcurrencyOverflowWait leaves excess ticks in the dispatcher until a
+	// running execution finishes.
+	ConcurrencyOverflowWait concurrencyOverflow = iota
+	// ConcurrencyOverflowSkip records excess ticks as skipped executions.
+	ConcurrencyOverflowSkip
+)
+
+func (o concurrencyOverflow) String() string {
+	switch o {
+	case ConcurrencyOverflowWait:
+		return "wait"
+	case ConcurrencyOverflowSkip:
+		return "skip"
+	default:
+		return "unknown"
+	}
+}
+
 type Worker struct {
 	wg sync.WaitGroup
 
@@ -43,15 +77,37 @@ type Worker struct {
 
 	policy workerPolicy
 
-	state   atomic.Value
-	running atomic.Bool
+	// slots bounds the executions running at once under
+	// WorkerPolicyParallel; nil means unbounded.
+	slots chan struct{}
+
+	state    atomic.Value
+	running  atomic.Bool
+	inFlight atomic.Int32
 
 	logger Logger
 }
 
 func (w *Worker) Status() workerState { return w.state.Load().(workerState) }
 
-func (w *Worker) Run(ctx context.Context) {
+// InFlight returns the number of executions currently running.
+func (w *Worker) InFlight() int { return int(w.inFlight.Load()) }
+
+func (w *Worker) execute(ctx context.Context, tick *Tick) {
+	w.inFlight.Add(1)
+	defer w.inFlight.Add(-1)
+	w.task.Execute(ctx, tick)
+}
+
+func (w *Worker) waitsForSlot() bool {
+	return w.slots != nil && w.task.concurrencyOverflow == ConcurrencyOverflowWait
+}
+
+func (w *Worker) Run(ctx context.Context) { w.run(ctx, nil) }
+
+// run executes dispatched ticks until ctx is done, or until finish is closed
+// and the ticks left in the dispatcher have been executed.
+func (w *Worker) run(ctx context.Context, finish <-chan struct{}) {
 	if w.state.Load().(workerState) != workerIdle {
 		return
 	}
@@ -59,8 +115,33 @@ func (w *Worker) Run(ctx context.Context) {
 	defer w.state.Store(workerIdle)
 	w.state.Store(workerRunning)
 
+	finishing := false
 	for {
+		if finishing && w.dispatcher.Size() == 0 {
+			w.logger.Infof(
+				"Worker for task '%s' finished: no more ticks", w.task.Name(),
+			)
+			w.wg.Wait()
+			return
+		}
+
+		// Reserve a slot before dequeuing so that excess ticks keep
+		// waiting in the dispatcher.
+		if w.waitsForSlot() {
+			select {
+			case w.slots <- struct{}{}:
+			case <-ctx.Done():
+				w.shutdown()
+				return
+			}
+		}
+
 		select {
+		case <-finish:
+			finishing, finish = true, nil
+			if w.waitsForSlot() {
+				<-w.slots
+			}
 		case tick, ok := <-w.dispatcher.Dequeue():
 			if !ok {
 				w.logger.Infof(
@@ -73,36 +154,59 @@ func (w *Worker) Run(ctx context.Context) {
 
 			switch w.policy {
 			case WorkerPolicyParallel:
-				// TODO: maximum concurrency limit handling
+				if w.slots != nil && !w.waitsForSlot() {
+					select {
+					case w.slots <- struct{}{}:
+					default:
+						w.logger.With("tick", tick.currentTick).Warnf(
+							"Skipping execution of task '%s': %d executions already running",
+							w.task.Name(), cap(w.slots),
+						)
+						w.task.skip(tick, store.SkipReasonMaxConcurrency)
+						continue
+					}
+				}
+
 				w.wg.Add(1)
-				go func() { defer w.wg.Done(); w.task.Execute(ctx, tick) }()
+				go func() {
+					defer w.wg.Done()
+					if w.slots != nil {
+						defer func() { <-w.slots }()
+					}
+					w.execute(ctx, tick)
+				}()
 			case WorkerPolicySerial:
 				// TODO: timeout handling for serial execution
-				w.task.Execute(ctx, tick)
+				w.execute(ctx, tick)
 			case WorkerPolicySkipIfBusy:
 				if w.running.CompareAndSwap(false, true) {
 					w.wg.Add(1)
 					go func() {
 						defer w.running.Store(false)
 						defer w.wg.Done()
-						w.task.Execute(ctx, tick)
+						w.execute(ctx, tick)
 					}()
 				} else {
-					w.logger.Warnf(
+					w.logger.With("tick", tick.currentTick).Warnf(
 						"Skipping execution of task '%s': already running",
 						w.task.Name(),
 					)
+					w.task.skip(tick, store.SkipReasonBusy)
 				}
 			}
 		case <-ctx.Done():
-			w.logger.Infof("Shutting down worker for task '%s'", w.task.Name())
-			w.wg.Wait()
-			w.logger.Infof("Worker for task '%s' shutdown complete", w.task.Name())
+			w.shutdown()
 			return
 		}
 	}
 }
 
+func (w *Worker) shutdown() {
+	w.logger.Infof("Shutting down worker for task '%s'", w.task.Name())
+	w.wg.Wait()
+	w.logger.Infof("Worker for task '%s' shutdown complete", w.task.Name())
+}
+
 func newWorker(
 	task *Task,
 	dispatcher Dispatcher,
@@ -112,9 +216,12 @@ func newWorker(
 	w := &Worker{
 		task:       task,
 		policy:     policy,
-		logger:     logger,
+		logger:     logger.With("task", task.Name()),
 		dispatcher: dispatcher,
 	}
+	if policy == WorkerPolicyParallel && task.maxConcurrency > 0 {
+		w.slots = make(chan struct{}, task.maxConcurrency)
+	}
 	w.state.Store(workerIdle)
 	return w
 }
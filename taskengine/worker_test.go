@@ -0,0 +1,101 @@
+package taskengine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+func waitFor(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow concurrencyOverflow
+		queued   int
+		skipped  int
+	}{
+		{"wait", ConcurrencyOverflowWait, 2, 0},
+		{"skip", ConcurrencyOverflowSkip, 0, 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			job := func(ctx *Context) error { <-release; return nil }
+
+			task, s := newTestTask(t, job, WithMaxConcurrency(2), WithConcurrencyOverflow(tc.overflow))
+			dispatcher := newDispatcher(10)
+			worker := newWorker(task, dispatcher, WorkerPolicyParallel, &mockLogger{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() { defer close(done); worker.Run(ctx) }()
+
+			for i := 0; i < 4; i++ {
+				if err := dispatcher.Enqueue(&Tick{currentTick: time.Now()}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			waitFor(t, func() bool {
+				return worker.InFlight() == 2 && dispatcher.Size() == tc.queued &&
+					len(listExecutions(t, s)) == tc.skipped
+			}, "expected 2 executions in flight")
+
+			time.Sleep(10 * time.Millisecond)
+			if got := worker.InFlight(); got != 2 {
+				t.Errorf("expected at most 2 executions in flight, got %d", got)
+			}
+
+			if got := skipReasons(t, s)[store.SkipReasonMaxConcurrency]; got != tc.skipped {
+				t.Errorf("expected %d max concurrency skips, got %d", tc.skipped, got)
+			}
+
+			close(release)
+			waitFor(t, func() bool {
+				return len(listExecutions(t, s)) == 4
+			}, "expected every tick to be recorded")
+
+			cancel()
+			<-done
+		})
+	}
+}
+
+func TestWorkerSkipIfBusyRecordsSkip(t *testing.T) {
+	release := make(chan struct{})
+	job := func(ctx *Context) error { <-release; return nil }
+
+	task, s := newTestTask(t, job)
+	dispatcher := newDispatcher(10)
+	worker := newWorker(task, dispatcher, WorkerPolicySkipIfBusy, &mockLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); worker.Run(ctx) }()
+
+	dispatcher.Enqueue(&Tick{currentTick: time.Now()})
+	waitFor(t, func() bool { return worker.InFlight() == 1 }, "expected worker to be busy")
+	dispatcher.Enqueue(&Tick{currentTick: time.Now()})
+
+	waitFor(t, func() bool {
+		return skipReasons(t, s)[store.SkipReasonBusy] == 1
+	}, "expected busy tick to be recorded as skipped")
+
+	close(release)
+	cancel()
+	<-done
+}
@@ -0,0 +1,242 @@
+package taskengine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+)
+
+// Workflow runs a set of tasks as a directed acyclic graph. Its trigger fires
+// the tasks without dependencies, and every other task runs once all the
+// tasks it depends on succeeded for the same tick. Each task is saved under
+// its own name, linked to the execution of the workflow.
+type Workflow struct {
+	task  *Task
+	tasks []*Task
+}
+
+func (w *Workflow) Name() string { return w.task.name }
+
+func (w *Workflow) taskNamed(name string) *Task {
+	for _, task := range w.tasks {
+		if task.name == name {
+			return task
+		}
+	}
+	return nil
+}
+
+// NewWorkflow creates a workflow of tasks, whose dependencies are declared
+// with WithDependsOn. The options apply to the workflow execution itself.
+func NewWorkflow(name string, tasks []*Task, options ...taskOption) (*Workflow, error) {
+	if len(tasks) == 0 {
+		return nil, errors.New("workflow must have at least one task")
+	}
+
+	w := &Workflow{tasks: tasks}
+	task, err := NewTask(name, w.run, options...)
+	if err != nil {
+		return nil, err
+	}
+	w.task = task
+	return w, nil
+}
+
+// WithDependsOn makes a workflow task run only after the named tasks of the
+// same workflow succeeded.
+func WithDependsOn(upstream ...string) taskOption {
+	return func(t *Task) {
+		t.upstream = append(t.upstream, upstream...)
+	}
+}
+
+// validate checks that the tasks of the workflow have unique names and
+// depend on tasks of the workflow without forming a cycle.
+func (w *Workflow) validate() error {
+	byName := make(map[string]*Task, len(w.tasks))
+	for _, task := range w.tasks {
+		if _, exists := byName[task.name]; exists {
+			return fmt.Errorf("task '%s' is added to workflow '%s' twice", task.name, w.Name())
+		}
+		byName[task.name] = task
+	}
+
+	pending := make(map[string]int, len(w.tasks))
+	downstream := make(map[string][]*Task, len(w.tasks))
+	for _, task := range w.tasks {
+		for _, upstream := range task.upstream {
+			if _, exists := byName[upstream]; !exists {
+				return fmt.Errorf(
+					"%w: task '%s' depends on '%s'", ErrorDependencyNotFound, task.name, upstream,
+				)
+			}
+			pending[task.name]++
+			downstream[upstream] = append(downstream[upstream], task)
+		}
+	}
+
+	// Tasks are ordered after their dependencies; the ones left out are
+	// part of or behind a cycle.
+	order := make([]*Task, 0, len(w.tasks))
+	for _, task := range w.tasks {
+		if pending[task.name] == 0 {
+			order = append(order, task)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, next := range downstream[order[i].name] {
+			if pending[next.name]--; pending[next.name] == 0 {
+				order = append(order, next)
+			}
+		}
+	}
+
+	if len(order) < len(w.tasks) {
+		var cycle []string
+		for _, task := range w.tasks {
+			if pending[task.name] > 0 {
+				cycle = append(cycle, task.name)
+			}
+		}
+		return fmt.Errorf(
+			"%w in workflow '%s' between tasks %s",
+			ErrorDependencyCycle, w.Name(), strings.Join(cycle, ", "),
+		)
+	}
+	return nil
+}
+
+type workflowNode struct {
+	done   chan struct{}
+	status store.ExecutionStatus
+}
+
+// run is the job of the workflow. It fires every task for the tick of the
+// workflow and fails unless all of them succeeded.
+func (w *Workflow) run(ctx *Context) error {
+	tick := &Tick{
+		lastTick:          ctx.LastTick(),
+		currentTick:       ctx.CurrentTick(),
+		manual:            ctx.Manual(),
+		params:            ctx.Params(),
+		parentExecutionID: ctx.ExecutionID(),
+	}
+
+	nodes := make(map[string]*workflowNode, len(w.tasks))
+	for _, task := range w.tasks {
+		nodes[task.name] = &workflowNode{done: make(chan struct{})}
+	}
+
+	for _, task := range w.tasks {
+		go func(task *Task, node *workflowNode) {
+			defer close(node.done)
+
+			ready := true
+			for _, upstream := range task.upstream {
+				<-nodes[upstream].done
+				ready = ready && nodes[upstream].status == store.ExecutionStatusSuccess
+			}
+
+			// Tasks left behind by a shutdown are not recorded.
+			if ctx.Err() != nil {
+				return
+			}
+
+			if ready {
+				node.status = task.execute(ctx, tick)
+				return
+			}
+
+			task.logger.With("tick", tick.currentTick).Warnf(
+				"Skipping task '%s' of workflow '%s': upstream task did not succeed",
+				task.name, w.Name(),
+			)
+			task.skip(tick, store.SkipReasonUpstreamFailed)
+			node.status = store.ExecutionStatusSkipped
+		}(task, nodes[task.name])
+	}
+
+	var failed []string
+	for _, task := range w.tasks {
+		node := nodes[task.name]
+		<-node.done
+		if node.status != store.ExecutionStatusSuccess {
+			failed = append(failed, task.name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("workflow tasks did not succeed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// workflowTaskTrigger stands in for the trigger of a workflow task, which
+// only runs when its workflow fires it.
+type workflowTaskTrigger struct {
+	workflow string
+	upstream []string
+}
+
+func (t *workflowTaskTrigger) String() string {
+	return fmt.Sprintf("Workflow(name=%s, dependsOn=%v)", t.workflow, t.upstream)
+}
+
+func (t *workflowTaskTrigger) Next(lastRun time.Time) (time.Time, error) {
+	return time.Time{}, errors.New("workflow tasks are fired by their workflow")
+}
+
+// RegisterWorkflow validates the dependencies of workflow and registers it
+// as a task named after the workflow, scheduled by trigger. Its tasks are
+// not registered on their own and only run as part of the workflow.
+func (e *Engine) RegisterWorkflow(
+	workflow *Workflow,
+	policy workerPolicy,
+	trigger Trigger,
+	catchUpEnabled bool,
+	maxExecutionLag int,
+) error {
+	e.mu.Lock()
+	if _, exists := e.supervisors[workflow.Name()]; exists {
+		e.mu.Unlock()
+		e.logger.Warnf("Workflow '%s' is already registered", workflow.Name())
+		return nil
+	}
+	for _, task := range workflow.tasks {
+		_, registered := e.supervisors[task.name]
+		_, inWorkflow := e.workflowTasks[task.name]
+		if registered || inWorkflow {
+			e.mu.Unlock()
+			return fmt.Errorf("workflow task '%s': %w", task.name, ErrorTaskAlreadyRegistered)
+		}
+	}
+	e.mu.Unlock()
+
+	if err := workflow.validate(); err != nil {
+		return err
+	}
+
+	for _, task := range workflow.tasks {
+		taskTrigger := &workflowTaskTrigger{workflow: workflow.Name(), upstream: task.upstream}
+		if err := e.saveTask(task, policy, taskTrigger); err != nil {
+			return fmt.Errorf("workflow task '%s': %w", task.name, err)
+		}
+		e.setupTask(task, policy)
+	}
+
+	if err := e.RegisterTask(workflow.task, policy, trigger, catchUpEnabled, maxExecutionLag); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.workflows[workflow.Name()] = workflow
+	for _, task := range workflow.tasks {
+		e.workflowTasks[task.name] = workflow
+		task.setEngineMiddleware(e.middleware)
+	}
+	e.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,251 @@
+package taskengine
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MAD-py/go-taskengine/taskengine/store"
+	"github.com/MAD-py/go-taskengine/taskengine/store/memory"
+)
+
+func newWorkflowTask(t *testing.T, name string, job Job, upstream ...string) *Task {
+	t.Helper()
+
+	task, err := NewTask(name, job, WithDependsOn(upstream...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return task
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream map[string][]string
+		expected error
+	}{
+		{"valid", map[string][]string{"a": nil, "b": {"a"}, "c": {"a"}, "d": {"b", "c"}}, nil},
+		{"unknown dependency", map[string][]string{"a": nil, "b": {"missing"}}, ErrorDependencyNotFound},
+		{"cycle", map[string][]string{"a": nil, "b": {"a", "c"}, "c": {"b"}}, ErrorDependencyCycle},
+		{"self dependency", map[string][]string{"a": {"a"}}, ErrorDependencyCycle},
+		{"no root", map[string][]string{"a": {"b"}, "b": {"a"}}, ErrorDependencyCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tasks []*Task
+			for _, name := range []string{"a", "b", "c", "d"} {
+				if upstream, ok := tt.upstream[name]; ok {
+					tasks = append(tasks, newWorkflowTask(t, name, noopJob, upstream...))
+				}
+			}
+
+			workflow, err := NewWorkflow("workflow", tasks)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err := workflow.validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("expected error %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestWorkflowValidateDuplicateTask(t *testing.T) {
+	workflow, err := NewWorkflow("workflow", []*Task{
+		newWorkflowTask(t, "a", noopJob),
+		newWorkflowTask(t, "a", noopJob),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := workflow.validate(); err == nil {
+		t.Error("expected an error for a duplicate task")
+	}
+}
+
+func TestNewWorkflowWithoutTasks(t *testing.T) {
+	if _, err := NewWorkflow("workflow", nil); err == nil {
+		t.Error("expected an error for a workflow without tasks")
+	}
+}
+
+func TestRegisterWorkflowRejectsCycle(t *testing.T) {
+	engine := newTestEngine(t)
+
+	workflow, _ := NewWorkflow("workflow", []*Task{
+		newWorkflowTask(t, "a", noopJob, "b"),
+		newWorkflowTask(t, "b", noopJob, "a"),
+	})
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+
+	err := engine.RegisterWorkflow(workflow, WorkerPolicySerial, trigger, false, 1)
+	if !errors.Is(err, ErrorDependencyCycle) {
+		t.Fatalf("expected ErrorDependencyCycle, got %v", err)
+	}
+	if _, err := engine.Task("workflow"); !errors.Is(err, ErrorTaskNotFound) {
+		t.Errorf("expected workflow not to be registered, got %v", err)
+	}
+}
+
+func runTestWorkflow(t *testing.T, workflow *Workflow) (*Engine, *store.Execution) {
+	t.Helper()
+
+	engine := newTestEngine(t)
+	trigger, _ := NewIntervalTrigger(time.Hour, true)
+	if err := engine.RegisterWorkflow(workflow, WorkerPolicySerial, trigger, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Start()
+	t.Cleanup(func() { engine.Shutdown() })
+
+	var execution *store.Execution
+	waitFor(t, func() bool {
+		execution, _ = engine.GetExecution("workflow", 1)
+		return execution != nil
+	}, "expected the workflow to run")
+	return engine, execution
+}
+
+func TestWorkflowRun(t *testing.T) {
+	var mu sync.Mutex
+	var finished []string
+	job := func(name string) Job {
+		return func(ctx *Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			finished = append(finished, name)
+			return nil
+		}
+	}
+
+	workflow, _ := NewWorkflow("workflow", []*Task{
+		newWorkflowTask(t, "load", job("load"), "transform1", "transform2", "transform3"),
+		newWorkflowTask(t, "transform1", job("transform1"), "extract"),
+		newWorkflowTask(t, "transform2", job("transform2"), "extract"),
+		newWorkflowTask(t, "transform3", job("transform3"), "extract"),
+		newWorkflowTask(t, "extract", job("extract")),
+	})
+
+	engine, execution := runTestWorkflow(t, workflow)
+
+	if execution.Status != store.ExecutionStatusSuccess {
+		t.Fatalf("expected workflow to succeed, got %s: %s", execution.Status, execution.ErrorMsg)
+	}
+	if len(finished) != 5 || finished[0] != "extract" || finished[4] != "load" {
+		t.Fatalf("expected extract first and load last, got %v", finished)
+	}
+
+	for _, name := range finished {
+		executions, err := engine.ListExecutions(name, &store.ExecutionFilter{
+			ParentExecutionID: execution.ExecutionID,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(executions) != 1 || !executions[0].Tick.Equal(execution.Tick) {
+			t.Errorf("expected one execution of %s linked to the workflow tick, got %d", name, len(executions))
+		}
+	}
+}
+
+func TestWorkflowUpstreamFailure(t *testing.T) {
+	loaded := false
+	workflow, _ := NewWorkflow("workflow", []*Task{
+		newWorkflowTask(t, "extract", noopJob),
+		newWorkflowTask(t, "transform", func(ctx *Context) error { return errors.New("failed") }, "extract"),
+		newWorkflowTask(t, "load", func(ctx *Context) error { loaded = true; return nil }, "transform"),
+	})
+
+	engine, execution := runTestWorkflow(t, workflow)
+
+	if execution.Status != store.ExecutionStatusError {
+		t.Errorf("expected workflow to fail, got %s", execution.Status)
+	}
+	if loaded {
+		t.Error("expected load not to run after transform failed")
+	}
+
+	load, err := engine.GetExecution("load", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if load.Status != store.ExecutionStatusSkipped || load.SkipReason != store.SkipReasonUpstreamFailed {
+		t.Errorf("expected load skipped for %s, got %s (%s)", store.SkipReasonUpstreamFailed, load.Status, load.SkipReason)
+	}
+	if load.ParentExecutionID != execution.ExecutionID {
+		t.Errorf("expected load linked to %q, got %q", execution.ExecutionID, load.ParentExecutionID)
+	}
+}
+
+func TestWorkflowTasksAreTracked(t *testing.T) {
+	ms := memory.NewStore()
+	engine, err := New(
+		ms,
+		WithLoggerFactory(func(string) Logger { return &mockLogger{} }),
+		WithRetention(store.RetentionPolicy{MaxRows: 1}, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	workflow, _ := NewWorkflow("workflow", []*Task{
+		newWorkflowTask(t, "extract", noopJob),
+		newWorkflowTask(t, "load", noopJob, "extract"),
+	})
+	trigger, _ := NewIntervalTrigger(time.Hour, false)
+	if err := engine.RegisterWorkflow(workflow, WorkerPolicySerial, trigger, false, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tasks, err := engine.Tasks()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].Name != "extract" || tasks[1].Name != "load" || tasks[2].Name != "workflow" {
+		t.Fatalf("expected extract, load and workflow, got %d tasks", len(tasks))
+	}
+	if tasks[1].Workflow != "workflow" || tasks[2].Workflow != "" {
+		t.Errorf("expected load to run in workflow, got %q and %q", tasks[1].Workflow, tasks[2].Workflow)
+	}
+
+	info, err := engine.Task("load")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Policy != WorkerPolicySerial || info.Status != store.TaskStatusIdle {
+		t.Errorf("expected serial idle task, got %s and %s", info.Policy, info.Status)
+	}
+
+	other, _ := NewTask("load", noopJob)
+	if err := engine.RegisterTask(other, WorkerPolicySerial, trigger, false, 1); !errors.Is(err, ErrorTaskAlreadyRegistered) {
+		t.Errorf("expected ErrorTaskAlreadyRegistered, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := ms.SaveExecution("load", &store.ExecutionInfo{Status: store.ExecutionStatusSuccess}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	engine.pruneExecutions()
+
+	executions, err := engine.ListExecutions("load", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executions) != 1 {
+		t.Errorf("expected the janitor to keep 1 execution of load, got %d", len(executions))
+	}
+
+	if err := engine.RemoveTask("workflow"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := engine.Task("load"); !errors.Is(err, ErrorTaskNotFound) {
+		t.Errorf("expected workflow tasks to be removed with the workflow, got %v", err)
+	}
+}